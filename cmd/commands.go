@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Maxim-Ba/cv-backend/config"
+	"github.com/Maxim-Ba/cv-backend/internal/dbconn"
+	"github.com/Maxim-Ba/cv-backend/internal/services"
+)
+
+// runCommand выполняет служебную команду CLI вместо запуска сервера
+func runCommand(db *dbconn.DB, cfg *config.Config, args []string) error {
+	repos := defineRepositories(db)
+
+	switch args[0] {
+	case "create-admin":
+		return createAdmin(repos, args[1:])
+	case "gc-files":
+		return gcFiles(repos, cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// createAdmin создает администратора.
+// Пароль читается из stdin, если не передан флагом -password.
+func createAdmin(repos *Repositories, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := fs.String("username", "", "admin login")
+	password := fs.String("password", "", "admin password (read from stdin if empty)")
+	role := fs.String("role", services.RoleOwner, "user role: "+strings.Join(services.Roles, ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *password == "" {
+		fmt.Print("Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	auth := services.NewAuthService(repos.UserRepository, repos.SessionRepository, repos.RoleRepository, 0)
+	user, err := auth.CreateUser(*username, *password, *role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("User %q (%s) created with id %d\n", user.Username, user.Role, user.ID)
+	return nil
+}
+
+// gcFiles удаляет загруженные файлы, которые не используются ни в одном логотипе.
+// С флагом -dry-run только выводит, какие файлы были бы удалены.
+func gcFiles(repos *Repositories, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("gc-files", flag.ContinueOnError)
+	grace := fs.Duration("grace", cfg.FileGCGrace, "keep unreferenced files uploaded less than this long ago")
+	dryRun := fs.Bool("dry-run", false, "report files that would be deleted without deleting them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	storage, err := newStorage(cfg)
+	if err != nil {
+		return err
+	}
+	files := services.NewFileService(storage, repos.FileRepository, services.DefaultMaxFileSize)
+	swept, err := files.SweepOrphans(*grace, *dryRun)
+	for _, file := range swept {
+		fmt.Printf("%s\t%d\t%s\t%s\n", file.CreatedAt.Format(time.RFC3339), file.Size, file.ContentType, strings.Join(file.Keys, " "))
+	}
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Printf("%d unreferenced files would be deleted\n", len(swept))
+	} else {
+		fmt.Printf("%d unreferenced files deleted\n", len(swept))
+	}
+	return nil
+}
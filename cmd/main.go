@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"log/slog"
@@ -10,6 +11,7 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/Maxim-Ba/cv-backend/config"
 	"github.com/Maxim-Ba/cv-backend/internal/dbconn"
@@ -28,13 +30,18 @@ func main() {
 	defer cancel()
 
 	cfg := config.GetConfig()
-	fmt.Printf("Config: %+v\n", cfg)
 	logger.InitLogger(cfg)
 	db, err := dbconn.New(*cfg)
 
 	if err != nil {
 		log.Panicf("%v", err)
 	}
+	if len(os.Args) > 1 {
+		if err := runCommand(db, cfg, os.Args[1:]); err != nil {
+			log.Fatalf("%v", err)
+		}
+		return
+	}
 	router, err := initApplication(ctx, db, cfg)
 	if err != nil {
 		log.Panicf("%v", err)
@@ -69,28 +76,131 @@ func main() {
 }
 
 func initApplication(ctx context.Context, db *dbconn.DB, cfg *config.Config) (*router.Router, error) {
+	// Формы админки защищены CSRF-токенами, ключ которых должен переживать перезапуски
+	if len(cfg.CSRFKey) == 0 {
+		return nil, fmt.Errorf("CSRF_KEY is not set: generate one with openssl rand -hex 32")
+	}
+
 	// Инициализация репозиториев
 	repos := defineRepositories(db)
 	
 	// Инициализация сервисов с использованием репозиториев
+	formTokens := services.NewFormTokens(formTokenSecret(cfg), 0)
+	feedbackService := services.NewFeedbackService(repos.FeedbackRepository,
+		services.HoneypotChecker{},
+		services.SubmitTimeChecker{Tokens: formTokens, MinDelay: cfg.FeedbackMinSubmitTime},
+		services.ContentChecker{MinLength: services.DefaultSpamMinMessageLength, MaxLinks: services.DefaultSpamMaxLinks},
+	)
+	storage, err := newStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	fileService := services.NewFileService(storage, repos.FileRepository, services.DefaultMaxFileSize)
+	techService := services.NewTechService(repos.TechRepository)
+	educationService := services.NewEducationService(repos.EducationRepository)
+	workHistoryService := services.NewWorkHistoryService(repos.WorkHistoryRepository)
+	cvProfile := services.Profile{
+		Name:     cfg.CVName,
+		Position: cfg.CVPosition,
+		About:    cfg.CVAbout,
+		Email:    cfg.CVEmail,
+		Location: cfg.CVLocation,
+		Links:    cfg.CVLinks,
+	}
+	authService := services.NewAuthService(repos.UserRepository, repos.SessionRepository, repos.RoleRepository, cfg.SessionTTL)
 	deps := &router.Dependencies{
 		TagService:         services.NewTagServise(repos.TagRepository),
-		TechService:        services.NewTechService(repos.TechRepository),
-		EducationService:   services.NewEducationService(repos.EducationRepository),
-		WorkHistoryService: services.NewWorkHistoryService(repos.WorkHistoryRepository),
+		TechService:        techService,
+		EducationService:   educationService,
+		WorkHistoryService: workHistoryService,
+		AuthService:        authService,
+		ApiTokenService:    services.NewApiTokenService(repos.ApiTokenRepository, authService),
+		FeedbackService:    feedbackService,
+		FormTokens:         formTokens,
+		FileService:        fileService,
+		SearchService:      services.NewSearchService(repos.SearchRepository),
+		CVService:          services.NewCVService(cvProfile, workHistoryService, educationService, techService),
 	}
 	
+	// Уведомления о новых сообщениях отправляются в фоне, если настроен SMTP
+	if cfg.SMTPHost != "" {
+		emailService := services.NewEmailService(repos.EmailQueueRepository, services.SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}, cfg.SMTPTo)
+		feedbackService.SetNotifier(emailService)
+		go emailService.Run(ctx, services.DefaultEmailPollInterval)
+	}
+
+	// Неиспользуемые загруженные файлы удаляются в фоне; FILE_GC_INTERVAL=0 отключает сборщик
+	if cfg.FileGCInterval > 0 {
+		go fileService.RunSweeper(ctx, cfg.FileGCInterval, cfg.FileGCGrace)
+	}
+
 	// Инициализация роутера с зависимостями
-	r := router.New(deps)
+	r := router.New(deps, cfg)
 	return r, nil
 }
 
+// newStorage создает хранилище файлов по STORAGE_BACKEND
+func newStorage(cfg *config.Config) (services.Storage, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		publicURL := cfg.StoragePublicURL
+		if publicURL == "" {
+			publicURL = "/files"
+		}
+		return services.LocalStorage{Dir: cfg.StorageDir, BaseURL: publicURL}, nil
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for s3 storage")
+		}
+		return &services.S3Storage{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.StoragePublicURL,
+			PathStyle: cfg.S3PathStyle,
+			Client:    &http.Client{Timeout: 30 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
+
+// formTokenSecret возвращает ключ подписи токенов формы обратной связи.
+// Без SECRET ключ случайный, и выданные токены перестают действовать после перезапуска.
+func formTokenSecret(cfg *config.Config) []byte {
+	if cfg.Secret != "" {
+		return []byte(cfg.Secret)
+	}
+	slog.Warn("SECRET is not set, using a random key for feedback form tokens")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Panicf("%v", err)
+	}
+	return secret
+}
+
 // Repositories структура для хранения всех репозиториев приложения
 type Repositories struct {
 	TagRepository         *repository.TagRepo
 	TechRepository        *repository.TechnologyRepo
 	EducationRepository   *repository.EducationRepo
 	WorkHistoryRepository *repository.WorkHistoryRepo
+	UserRepository        *repository.UserRepo
+	SessionRepository     *repository.SessionRepo
+	RoleRepository        *repository.RoleRepo
+	ApiTokenRepository    *repository.ApiTokenRepo
+	FeedbackRepository    *repository.FeedbackRepo
+	EmailQueueRepository  *repository.EmailQueueRepo
+	FileRepository        *repository.FileRepo
+	SearchRepository      *repository.SearchRepo
 }
 
 // defineRepositories создает экземпляры всех репозиториев
@@ -100,5 +210,13 @@ func defineRepositories(db *dbconn.DB) *Repositories {
 		TechRepository:        repository.NewTechnologyRepo(db.GetConnection()),
 		EducationRepository:   repository.NewEducationRepo(db.GetConnection()),
 		WorkHistoryRepository: repository.NewWorkHistoryRepo(db.GetConnection()),
+		UserRepository:        repository.NewUserRepo(db.GetConnection()),
+		SessionRepository:     repository.NewSessionRepo(db.GetConnection()),
+		RoleRepository:        repository.NewRoleRepo(db.GetConnection()),
+		ApiTokenRepository:    repository.NewApiTokenRepo(db.GetConnection()),
+		FeedbackRepository:    repository.NewFeedbackRepo(db.GetConnection()),
+		EmailQueueRepository:  repository.NewEmailQueueRepo(db.GetConnection()),
+		FileRepository:        repository.NewFileRepo(db.GetConnection()),
+		SearchRepository:      repository.NewSearchRepo(db.GetConnection()),
 	}
 }
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
 type Config struct {
 	Secret           string
 	ServerAddr       string
@@ -11,12 +17,51 @@ type Config struct {
 	MigrationPath    string
 	LogLevel         string
 	AppEnv           string
+	SessionTTL       time.Duration
+	SecureCookie     bool
+	// CSRFKey ключ подписи CSRF-токенов админки, 32 байта из CSRF_KEY в hex
+	CSRFKey []byte
+
+	FeedbackRateLimit     int
+	FeedbackRateWindow    time.Duration
+	FeedbackMinSubmitTime time.Duration
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTo       string
+
+	StorageBackend   string
+	StorageDir       string
+	StoragePublicURL string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3PathStyle      bool
+
+	FileGCInterval time.Duration
+	FileGCGrace    time.Duration
+
+	CVName     string
+	CVPosition string
+	CVAbout    string
+	CVEmail    string
+	CVLocation string
+	CVLinks    []string
 }
 
 var cfg Config
 
 func init() {
 	envs, err := parseEnv()
+	if err != nil {
+		panic(err)
+	}
+	csrfKey, err := parseCSRFKey(envs.CSRFKey)
 	if err != nil {
 		panic(err)
 	} else {
@@ -30,9 +75,58 @@ func init() {
 			MigrationPath:    envs.MigrationPath,
 			LogLevel:         envs.LogLevel,
 			AppEnv:           envs.AppEnv,
+			SessionTTL:       envs.SessionTTL,
+			SecureCookie:     envs.SecureCookie,
+			CSRFKey:          csrfKey,
+			Secret:           envs.Secret,
+
+			FeedbackRateLimit:     envs.FeedbackRateLimit,
+			FeedbackRateWindow:    envs.FeedbackRateWindow,
+			FeedbackMinSubmitTime: envs.FeedbackMinSubmitTime,
+
+			SMTPHost:     envs.SMTPHost,
+			SMTPPort:     envs.SMTPPort,
+			SMTPUsername: envs.SMTPUsername,
+			SMTPPassword: envs.SMTPPassword,
+			SMTPFrom:     envs.SMTPFrom,
+			SMTPTo:       envs.SMTPTo,
+
+			StorageBackend:   envs.StorageBackend,
+			StorageDir:       envs.StorageDir,
+			StoragePublicURL: envs.StoragePublicURL,
+			S3Endpoint:       envs.S3Endpoint,
+			S3Region:         envs.S3Region,
+			S3Bucket:         envs.S3Bucket,
+			S3AccessKey:      envs.S3AccessKey,
+			S3SecretKey:      envs.S3SecretKey,
+			S3PathStyle:      envs.S3PathStyle,
+
+			FileGCInterval: envs.FileGCInterval,
+			FileGCGrace:    envs.FileGCGrace,
+
+			CVName:     envs.CVName,
+			CVPosition: envs.CVPosition,
+			CVAbout:    envs.CVAbout,
+			CVEmail:    envs.CVEmail,
+			CVLocation: envs.CVLocation,
+			CVLinks:    envs.CVLinks,
 		}
 	}
 }
+
+// parseCSRFKey разбирает CSRF_KEY: 64 hex-символа (openssl rand -hex 32).
+// Пустое значение допустимо для команд cmd, сервер без ключа не запускается.
+func parseCSRFKey(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("CSRF_KEY must be 32 bytes in hex (64 characters), e.g. openssl rand -hex 32")
+	}
+	return key, nil
+}
+
 func GetConfig() *Config {
 	return &cfg
 }
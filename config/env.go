@@ -2,21 +2,57 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
 )
 
 type Envs struct {
-	PostgresHost     string `env:"POSTGRES_HOST"`
-	PostgresPort     string `env:"POSTGRES_PORT"`
-	PostgresUser     string `env:"POSTGRES_USER"`
-	PostgresPassword string `env:"POSTGRES_PASSWORD"`
-	PostgresDB       string `env:"POSTGRES_DB"`
-	ServerAddr       string `env:"SERVER_ADDRESS"`
-	MigrationPath    string `env:"MIGRATION_PATH"`
-	LogLevel         string `env:"LOG_LEVEL" default:"error"`
-	AppEnv           string `env:"APP_ENV" default:"development"`
+	PostgresHost     string        `env:"POSTGRES_HOST"`
+	PostgresPort     string        `env:"POSTGRES_PORT"`
+	PostgresUser     string        `env:"POSTGRES_USER"`
+	PostgresPassword string        `env:"POSTGRES_PASSWORD"`
+	PostgresDB       string        `env:"POSTGRES_DB"`
+	ServerAddr       string        `env:"SERVER_ADDRESS"`
+	MigrationPath    string        `env:"MIGRATION_PATH"`
+	LogLevel         string        `env:"LOG_LEVEL" default:"error"`
+	AppEnv           string        `env:"APP_ENV" default:"development"`
+	SessionTTL       time.Duration `env:"SESSION_TTL" envDefault:"24h"`
+	SecureCookie     bool          `env:"SECURE_COOKIE"`
+	CSRFKey          string        `env:"CSRF_KEY"`
+	Secret           string        `env:"SECRET"`
+
+	FeedbackRateLimit     int           `env:"FEEDBACK_RATE_LIMIT" envDefault:"5"`
+	FeedbackRateWindow    time.Duration `env:"FEEDBACK_RATE_WINDOW" envDefault:"1h"`
+	FeedbackMinSubmitTime time.Duration `env:"FEEDBACK_MIN_SUBMIT_TIME" envDefault:"3s"`
+
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+	SMTPTo       string `env:"SMTP_TO"`
+
+	StorageBackend   string `env:"STORAGE_BACKEND" envDefault:"local"`
+	StorageDir       string `env:"STORAGE_DIR" envDefault:"uploads"`
+	StoragePublicURL string `env:"STORAGE_PUBLIC_URL"`
+	S3Endpoint       string `env:"S3_ENDPOINT"`
+	S3Region         string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket         string `env:"S3_BUCKET"`
+	S3AccessKey      string `env:"S3_ACCESS_KEY"`
+	S3SecretKey      string `env:"S3_SECRET_KEY"`
+	S3PathStyle      bool   `env:"S3_PATH_STYLE" envDefault:"true"`
+
+	FileGCInterval time.Duration `env:"FILE_GC_INTERVAL" envDefault:"24h"`
+	FileGCGrace    time.Duration `env:"FILE_GC_GRACE" envDefault:"24h"`
+
+	CVName     string   `env:"CV_NAME"`
+	CVPosition string   `env:"CV_POSITION"`
+	CVAbout    string   `env:"CV_ABOUT"`
+	CVEmail    string   `env:"CV_EMAIL"`
+	CVLocation string   `env:"CV_LOCATION"`
+	CVLinks    []string `env:"CV_LINKS" envSeparator:","`
 }
 
 func parseEnv() (*Envs, error) {
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+
+	"github.com/Maxim-Ba/cv-backend/internal/services"
+)
+
+// SessionCookieName имя cookie с токеном сессии администратора
+const SessionCookieName = "session"
+
+type contextKey string
+
+const principalContextKey contextKey = "principal"
+
+// Authenticator проверяет токен сессии или API-токен и возвращает пользователя с правами
+type Authenticator interface {
+	Authenticate(token string) (services.Principal, error)
+}
+
+// TokenAuth проверяет заголовок Authorization: Bearer и кладет владельца токена в контекст.
+// Запросы с токеном не используют cookie, поэтому для них отключается проверка CSRF —
+// middleware должен стоять до csrf.Protect. Недействительный токен сразу получает 401.
+func TokenAuth(a Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				writeJSONError(w, http.StatusUnauthorized, "Unsupported authorization scheme")
+				return
+			}
+			principal, err := a.Authenticate(strings.TrimSpace(token))
+			if err != nil {
+				writeJSONError(w, http.StatusUnauthorized, "Invalid API token")
+				return
+			}
+			r = csrf.UnsafeSkipCheck(r)
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// SessionAuth читает cookie сессии и, если она действительна, кладет пользователя в контекст.
+// Запросы без сессии пропускаются дальше — доступ ограничивают RequireAdmin и RequirePermission.
+func SessionAuth(a Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := PrincipalFromContext(r.Context()); ok {
+				// Уже аутентифицирован токеном
+				next.ServeHTTP(w, r)
+				return
+			}
+			cookie, err := r.Cookie(SessionCookieName)
+			if err == nil && cookie.Value != "" {
+				if principal, err := a.Authenticate(cookie.Value); err == nil {
+					r = r.WithContext(WithPrincipal(r.Context(), principal))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAdmin перенаправляет пользователей без сессии на страницу входа.
+// API-токены не дают доступа к админке.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := PrincipalFromContext(r.Context()); !ok || principal.TokenID != 0 {
+			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission отвечает 401 на запросы без действительной сессии
+// и 403, если у пользователя нет указанного права
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				writeJSONError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			if !principal.Can(permission) {
+				writeJSONError(w, http.StatusForbidden, "Forbidden: "+permission+" permission required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithPrincipal возвращает контекст с аутентифицированным пользователем
+func WithPrincipal(ctx context.Context, principal services.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey, principal)
+}
+
+// PrincipalFromContext возвращает аутентифицированного пользователя из контекста
+func PrincipalFromContext(ctx context.Context) (services.Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey).(services.Principal)
+	return principal, ok
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+)
+
+// SkipCSRF отключает проверку CSRF для публичных эндпоинтов, которые вызываются
+// с других сайтов без cookie, например "POST /api/fb". Должен стоять до csrf.Protect.
+func SkipCSRF(routes ...string) func(http.Handler) http.Handler {
+	skip := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		skip[strings.TrimSuffix(route, "/")] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.Method+" "+strings.TrimSuffix(r.URL.Path, "/")] {
+				r = csrf.UnsafeSkipCheck(r)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit ограничивает число запросов с одного IP: не больше limit за скользящее окно window.
+// IP берется из RemoteAddr, поэтому middleware должен стоять после middleware.RealIP.
+func RateLimit(limit int, window time.Duration) func(http.Handler) http.Handler {
+	limiter := newIPLimiter(limit, window)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ok, retryAfter := limiter.allow(remoteIP(r)); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				writeJSONError(w, http.StatusTooManyRequests, "Too many requests")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ipLimiter хранит время последних запросов каждого IP
+type ipLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+	calls  int
+	now    func() time.Time
+}
+
+func newIPLimiter(limit int, window time.Duration) *ipLimiter {
+	return &ipLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// allow учитывает запрос и возвращает false и время до освобождения слота, если лимит исчерпан
+func (l *ipLimiter) allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.calls++
+	// Периодически удаляем IP, от которых давно не было запросов
+	if l.calls%1000 == 0 {
+		for key, times := range l.hits {
+			if len(times) == 0 || now.Sub(times[len(times)-1]) >= l.window {
+				delete(l.hits, key)
+			}
+		}
+	}
+
+	times := l.hits[ip]
+	for len(times) > 0 && now.Sub(times[0]) >= l.window {
+		times = times[1:]
+	}
+	if len(times) >= l.limit {
+		l.hits[ip] = times
+		return false, l.window - now.Sub(times[0])
+	}
+	l.hits[ip] = append(times, now)
+	return true, 0
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
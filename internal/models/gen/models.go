@@ -5,15 +5,82 @@
 package models
 
 import (
+	"time"
+
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"userId"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"tokenHash"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
 type Education struct {
 	ID           int64       `json:"id"`
 	Name         pgtype.Text `json:"name"`
 	Year         int32       `json:"year"`
 	Course       string      `json:"course"`
 	Organization string      `json:"organization"`
+	SearchVector interface{} `json:"-"`
+}
+
+type EmailQueue struct {
+	ID            int64      `json:"id"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	Attempts      int32      `json:"attempts"`
+	LastError     string     `json:"lastError"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	SentAt        *time.Time `json:"sentAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+type Feedback struct {
+	ID         int64     `json:"id"`
+	AuthorName string    `json:"authorName"`
+	Contact    string    `json:"contact"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Status     string    `json:"status"`
+	SourceIp   string    `json:"sourceIp"`
+	UserAgent  string    `json:"userAgent"`
+}
+
+type File struct {
+	ID          int64     `json:"id"`
+	Key         string    `json:"key"`
+	Url         string    `json:"url"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Keys        []string  `json:"keys"`
+	Urls        []string  `json:"urls"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type Role struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+}
+
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    int64     `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 type Tag struct {
@@ -28,21 +95,31 @@ type TechnologiesTag struct {
 }
 
 type Technology struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title"`
-	Description pgtype.Text `json:"description"`
-	LogoUrl     pgtype.Text `json:"logoUrl"`
+	ID           int64       `json:"id"`
+	Title        string      `json:"title"`
+	Description  pgtype.Text `json:"description"`
+	LogoUrl      pgtype.Text `json:"logoUrl"`
+	SearchVector interface{} `json:"-"`
+}
+
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"passwordHash"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Role         string    `json:"role"`
 }
 
 type WorkHistory struct {
-	ID          int64       `json:"id"`
-	Name        string      `json:"name"`
-	About       string      `json:"about"`
-	LogoUrl     []byte      `json:"logoUrl"`
-	PeriodStart pgtype.Date `json:"periodStart"`
-	PeriodEnd   pgtype.Date `json:"periodEnd"`
-	WhatIDid    []string    `json:"whatIDid"`
-	Projects    []string    `json:"projects"`
+	ID           int64               `json:"id"`
+	Name         string              `json:"name"`
+	About        string              `json:"about"`
+	LogoUrl      *types.LogoVariants `json:"logoUrl"`
+	PeriodStart  pgtype.Date         `json:"periodStart"`
+	PeriodEnd    pgtype.Date         `json:"periodEnd"`
+	WhatIDid     []string            `json:"whatIDid"`
+	Projects     []string            `json:"projects"`
+	SearchVector interface{}         `json:"-"`
 }
 
 type WorkHistoryTechnology struct {
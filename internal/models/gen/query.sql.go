@@ -8,6 +8,7 @@ package models
 import (
 	"context"
 
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -44,7 +45,7 @@ func (q *Queries) AddTechnologyToWorkHistory(ctx context.Context, arg AddTechnol
 const createEducation = `-- name: CreateEducation :one
 INSERT INTO education (id, name, year, course, organization)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, year, course, organization
+RETURNING id, name, year, course, organization, search_vector
 `
 
 type CreateEducationParams struct {
@@ -70,6 +71,7 @@ func (q *Queries) CreateEducation(ctx context.Context, arg CreateEducationParams
 		&i.Year,
 		&i.Course,
 		&i.Organization,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -96,7 +98,7 @@ func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, erro
 const createTechnology = `-- name: CreateTechnology :one
 INSERT INTO technology (id, title, description, logo_url)
 VALUES ($1, $2, $3, $4)
-RETURNING id, title, description, logo_url
+RETURNING id, title, description, logo_url, search_vector
 `
 
 type CreateTechnologyParams struct {
@@ -119,6 +121,7 @@ func (q *Queries) CreateTechnology(ctx context.Context, arg CreateTechnologyPara
 		&i.Title,
 		&i.Description,
 		&i.LogoUrl,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -126,18 +129,18 @@ func (q *Queries) CreateTechnology(ctx context.Context, arg CreateTechnologyPara
 const createWorkHistory = `-- name: CreateWorkHistory :one
 INSERT INTO work_history (id, name, about, logo_url, period_start, period_end, what_i_did, projects)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, name, about, logo_url, period_start, period_end, what_i_did, projects
+RETURNING id, name, about, logo_url, period_start, period_end, what_i_did, projects, search_vector
 `
 
 type CreateWorkHistoryParams struct {
-	ID          int64       `json:"id"`
-	Name        string      `json:"name"`
-	About       string      `json:"about"`
-	LogoUrl     []byte      `json:"logoUrl"`
-	PeriodStart pgtype.Date `json:"periodStart"`
-	PeriodEnd   pgtype.Date `json:"periodEnd"`
-	WhatIDid    []string    `json:"whatIDid"`
-	Projects    []string    `json:"projects"`
+	ID          int64               `json:"id"`
+	Name        string              `json:"name"`
+	About       string              `json:"about"`
+	LogoUrl     *types.LogoVariants `json:"logoUrl"`
+	PeriodStart pgtype.Date         `json:"periodStart"`
+	PeriodEnd   pgtype.Date         `json:"periodEnd"`
+	WhatIDid    []string            `json:"whatIDid"`
+	Projects    []string            `json:"projects"`
 }
 
 func (q *Queries) CreateWorkHistory(ctx context.Context, arg CreateWorkHistoryParams) (WorkHistory, error) {
@@ -161,6 +164,7 @@ func (q *Queries) CreateWorkHistory(ctx context.Context, arg CreateWorkHistoryPa
 		&i.PeriodEnd,
 		&i.WhatIDid,
 		&i.Projects,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -176,7 +180,7 @@ func (q *Queries) DeleteTechnology(ctx context.Context, id int64) error {
 }
 
 const getEducation = `-- name: GetEducation :one
-SELECT id, name, year, course, organization FROM education
+SELECT id, name, year, course, organization, search_vector FROM education
 WHERE id = $1
 `
 
@@ -189,6 +193,7 @@ func (q *Queries) GetEducation(ctx context.Context, id int64) (Education, error)
 		&i.Year,
 		&i.Course,
 		&i.Organization,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -206,7 +211,7 @@ func (q *Queries) GetTag(ctx context.Context, id int64) (Tag, error) {
 }
 
 const getTechnologiesByTag = `-- name: GetTechnologiesByTag :many
-SELECT t.id, t.title, t.description, t.logo_url FROM technology t
+SELECT t.id, t.title, t.description, t.logo_url, t.search_vector FROM technology t
 JOIN technologies_tag tt ON t.id = tt.technology_id
 WHERE tt.tag_id = $1
 ORDER BY t.title
@@ -226,6 +231,7 @@ func (q *Queries) GetTechnologiesByTag(ctx context.Context, tagID int64) ([]Tech
 			&i.Title,
 			&i.Description,
 			&i.LogoUrl,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -238,7 +244,7 @@ func (q *Queries) GetTechnologiesByTag(ctx context.Context, tagID int64) ([]Tech
 }
 
 const getTechnologiesByWorkHistory = `-- name: GetTechnologiesByWorkHistory :many
-SELECT t.id, t.title, t.description, t.logo_url FROM technology t
+SELECT t.id, t.title, t.description, t.logo_url, t.search_vector FROM technology t
 JOIN work_history_technology wht ON t.id = wht.technology_id
 WHERE wht.work_history_id = $1
 ORDER BY t.title
@@ -258,6 +264,7 @@ func (q *Queries) GetTechnologiesByWorkHistory(ctx context.Context, workHistoryI
 			&i.Title,
 			&i.Description,
 			&i.LogoUrl,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -270,7 +277,7 @@ func (q *Queries) GetTechnologiesByWorkHistory(ctx context.Context, workHistoryI
 }
 
 const getTechnology = `-- name: GetTechnology :one
-SELECT id, title, description, logo_url FROM technology
+SELECT id, title, description, logo_url, search_vector FROM technology
 WHERE id = $1
 `
 
@@ -282,12 +289,13 @@ func (q *Queries) GetTechnology(ctx context.Context, id int64) (Technology, erro
 		&i.Title,
 		&i.Description,
 		&i.LogoUrl,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const getWorkHistory = `-- name: GetWorkHistory :one
-SELECT id, name, about, logo_url, period_start, period_end, what_i_did, projects FROM work_history
+SELECT id, name, about, logo_url, period_start, period_end, what_i_did, projects, search_vector FROM work_history
 WHERE id = $1
 `
 
@@ -303,12 +311,13 @@ func (q *Queries) GetWorkHistory(ctx context.Context, id int64) (WorkHistory, er
 		&i.PeriodEnd,
 		&i.WhatIDid,
 		&i.Projects,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const listEducations = `-- name: ListEducations :many
-SELECT id, name, year, course, organization FROM education
+SELECT id, name, year, course, organization, search_vector FROM education
 ORDER BY year DESC
 `
 
@@ -327,6 +336,7 @@ func (q *Queries) ListEducations(ctx context.Context) ([]Education, error) {
 			&i.Year,
 			&i.Course,
 			&i.Organization,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -364,7 +374,7 @@ func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
 }
 
 const listTechnologies = `-- name: ListTechnologies :many
-SELECT id, title, description, logo_url FROM technology
+SELECT id, title, description, logo_url, search_vector FROM technology
 ORDER BY title
 `
 
@@ -382,6 +392,7 @@ func (q *Queries) ListTechnologies(ctx context.Context) ([]Technology, error) {
 			&i.Title,
 			&i.Description,
 			&i.LogoUrl,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -394,7 +405,7 @@ func (q *Queries) ListTechnologies(ctx context.Context) ([]Technology, error) {
 }
 
 const listWorkHistories = `-- name: ListWorkHistories :many
-SELECT id, name, about, logo_url, period_start, period_end, what_i_did, projects FROM work_history
+SELECT id, name, about, logo_url, period_start, period_end, what_i_did, projects, search_vector FROM work_history
 ORDER BY period_start DESC
 `
 
@@ -416,6 +427,7 @@ func (q *Queries) ListWorkHistories(ctx context.Context) ([]WorkHistory, error)
 			&i.PeriodEnd,
 			&i.WhatIDid,
 			&i.Projects,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -458,7 +470,7 @@ func (q *Queries) RemoveTechnologyFromWorkHistory(ctx context.Context, arg Remov
 }
 
 const searchTechnologies = `-- name: SearchTechnologies :many
-SELECT id, title, description, logo_url FROM technology
+SELECT id, title, description, logo_url, search_vector FROM technology
 WHERE title ILIKE '%' || $1 || '%'
    OR description ILIKE '%' || $1 || '%'
 ORDER BY title
@@ -478,6 +490,7 @@ func (q *Queries) SearchTechnologies(ctx context.Context, dollar_1 pgtype.Text)
 			&i.Title,
 			&i.Description,
 			&i.LogoUrl,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -493,7 +506,7 @@ const updateTechnology = `-- name: UpdateTechnology :one
 UPDATE technology
 SET title = $2, description = $3, logo_url = $4
 WHERE id = $1
-RETURNING id, title, description, logo_url
+RETURNING id, title, description, logo_url, search_vector
 `
 
 type UpdateTechnologyParams struct {
@@ -516,6 +529,7 @@ func (q *Queries) UpdateTechnology(ctx context.Context, arg UpdateTechnologyPara
 		&i.Title,
 		&i.Description,
 		&i.LogoUrl,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// LogoVariants URL логотипа и его уменьшенных копий, хранится в JSONB.
+// Для SVG все варианты указывают на один файл.
+type LogoVariants struct {
+	Original string `json:"original"`
+	Sm       string `json:"sm,omitempty"`
+	Md       string `json:"md,omitempty"`
+	Lg       string `json:"lg,omitempty"`
+}
+
+// Scan реализует sql.Scanner
+func (l *LogoVariants) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*l = LogoVariants{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("unsupported type %T for LogoVariants", src)
+	}
+}
+
+// Value реализует driver.Valuer
+func (l LogoVariants) Value() (driver.Value, error) {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// URLs возвращает все непустые URL вариантов без повторов
+func (l LogoVariants) URLs() []string {
+	var urls []string
+	for _, u := range []string{l.Original, l.Sm, l.Md, l.Lg} {
+		if u == "" {
+			continue
+		}
+		seen := false
+		for _, existing := range urls {
+			if existing == u {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
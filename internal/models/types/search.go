@@ -0,0 +1,12 @@
+package types
+
+// SearchHit результат полнотекстового поиска по резюме
+type SearchHit struct {
+	// Type тип найденной записи: technology, workHistory, education
+	Type  string `json:"type"`
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Snippet фрагмент текста, найденные слова выделены тегом <mark>
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+const apiTokenColumns = "id, user_id, name, token_hash, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+// ApiTokenRepo репозиторий для работы с таблицей api_tokens
+type ApiTokenRepo struct {
+	db *sql.DB
+}
+
+// NewApiTokenRepo создает новый экземпляр репозитория API-токенов
+func NewApiTokenRepo(db *sql.DB) *ApiTokenRepo {
+	return &ApiTokenRepo{
+		db: db,
+	}
+}
+
+// GetByHash получает токен по хешу
+func (a *ApiTokenRepo) GetByHash(hash string) (models.ApiToken, error) {
+	query := "SELECT " + apiTokenColumns + " FROM api_tokens WHERE token_hash = $1"
+
+	token, err := scanApiToken(a.db.QueryRow(query, hash))
+	if err == sql.ErrNoRows {
+		return models.ApiToken{}, fmt.Errorf("api token not found")
+	}
+	if err != nil {
+		return models.ApiToken{}, fmt.Errorf("failed to get api token: %w", err)
+	}
+
+	return token, nil
+}
+
+// ListByUser получает все токены пользователя, новые первыми
+func (a *ApiTokenRepo) ListByUser(userID int64) ([]models.ApiToken, error) {
+	query := "SELECT " + apiTokenColumns + " FROM api_tokens WHERE user_id = $1 ORDER BY id DESC"
+	rows, err := a.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []models.ApiToken{}
+	for rows.Next() {
+		token, err := scanApiToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Create сохраняет новый токен
+func (a *ApiTokenRepo) Create(token models.ApiToken) (models.ApiToken, error) {
+	query := `
+		INSERT INTO api_tokens (user_id, name, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + apiTokenColumns
+
+	created, err := scanApiToken(a.db.QueryRow(query,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		pq.Array(token.Scopes),
+		token.ExpiresAt,
+	))
+	if err != nil {
+		return models.ApiToken{}, fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	return created, nil
+}
+
+// Revoke отзывает токен пользователя
+func (a *ApiTokenRepo) Revoke(id, userID int64) error {
+	query := "UPDATE api_tokens SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL"
+	result, err := a.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("api token with id %d not found", id)
+	}
+
+	return nil
+}
+
+// TouchLastUsed обновляет время последнего использования токена
+func (a *ApiTokenRepo) TouchLastUsed(id int64) error {
+	query := "UPDATE api_tokens SET last_used_at = now() WHERE id = $1"
+	if _, err := a.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to update api token last use: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanApiToken(row rowScanner) (models.ApiToken, error) {
+	var token models.ApiToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		pq.Array(&token.Scopes),
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+	)
+	return token, err
+}
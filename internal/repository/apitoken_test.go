@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiTokenRepo_CreateGetList(t *testing.T) {
+	cleanupTable(t, "api_tokens")
+	cleanupTable(t, "users")
+	userRepo := NewUserRepo(testDB)
+	repo := NewApiTokenRepo(testDB)
+
+	user, err := userRepo.Create(models.User{Username: "admin", PasswordHash: "hash", Role: "owner"})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	created, err := repo.Create(models.ApiToken{
+		UserID:    user.ID,
+		Name:      "frontend build",
+		TokenHash: "token-hash",
+		Scopes:    []string{"read:*", "write:tech"},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, []string{"read:*", "write:tech"}, created.Scopes)
+	require.NotNil(t, created.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*created.ExpiresAt))
+	assert.Nil(t, created.LastUsedAt)
+	assert.Nil(t, created.RevokedAt)
+
+	got, err := repo.GetByHash("token-hash")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+	assert.Equal(t, "frontend build", got.Name)
+
+	_, err = repo.Create(models.ApiToken{UserID: user.ID, Name: "script", TokenHash: "other-hash", Scopes: []string{"read:tag"}})
+	require.NoError(t, err)
+
+	tokens, err := repo.ListByUser(user.ID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "script", tokens[0].Name)
+	assert.Nil(t, tokens[0].ExpiresAt)
+
+	_, err = repo.GetByHash("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestApiTokenRepo_RevokeAndTouch(t *testing.T) {
+	cleanupTable(t, "api_tokens")
+	cleanupTable(t, "users")
+	userRepo := NewUserRepo(testDB)
+	repo := NewApiTokenRepo(testDB)
+
+	user, err := userRepo.Create(models.User{Username: "admin", PasswordHash: "hash", Role: "owner"})
+	require.NoError(t, err)
+	other, err := userRepo.Create(models.User{Username: "editor", PasswordHash: "hash", Role: "editor"})
+	require.NoError(t, err)
+
+	created, err := repo.Create(models.ApiToken{UserID: user.ID, Name: "script", TokenHash: "token-hash", Scopes: []string{"read:*"}})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.TouchLastUsed(created.ID))
+	got, err := repo.GetByHash("token-hash")
+	require.NoError(t, err)
+	assert.NotNil(t, got.LastUsedAt)
+
+	err = repo.Revoke(created.ID, other.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	require.NoError(t, repo.Revoke(created.ID, user.ID))
+	got, err = repo.GetByHash("token-hash")
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+
+	err = repo.Revoke(created.ID, user.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+const emailQueueColumns = "id, recipient, subject, body, attempts, last_error, next_attempt_at, sent_at, created_at"
+
+// EmailQueueRepo репозиторий очереди исходящих писем
+type EmailQueueRepo struct {
+	db *sql.DB
+}
+
+// NewEmailQueueRepo создает новый экземпляр репозитория очереди писем
+func NewEmailQueueRepo(db *sql.DB) *EmailQueueRepo {
+	return &EmailQueueRepo{
+		db: db,
+	}
+}
+
+// Enqueue добавляет письмо в очередь
+func (e *EmailQueueRepo) Enqueue(email models.EmailQueue) (models.EmailQueue, error) {
+	query := `
+		INSERT INTO email_queue (recipient, subject, body)
+		VALUES ($1, $2, $3)
+		RETURNING ` + emailQueueColumns
+
+	created, err := scanEmailQueue(e.db.QueryRow(query, email.Recipient, email.Subject, email.Body))
+	if err != nil {
+		return models.EmailQueue{}, fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	return created, nil
+}
+
+// Due захватывает до limit неотправленных писем, время попытки которых наступило.
+// Захваченные письма откладываются на lease, чтобы их не взял другой экземпляр приложения.
+func (e *EmailQueueRepo) Due(limit int, maxAttempts int, lease time.Duration) ([]models.EmailQueue, error) {
+	query := `
+		UPDATE email_queue SET next_attempt_at = now() + make_interval(secs => $3)
+		WHERE id IN (
+			SELECT id FROM email_queue
+			WHERE sent_at IS NULL AND attempts < $2 AND next_attempt_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + emailQueueColumns
+
+	rows, err := e.db.Query(query, limit, maxAttempts, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due emails: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []models.EmailQueue
+	for rows.Next() {
+		email, err := scanEmailQueue(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		emails = append(emails, email)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return emails, nil
+}
+
+// MarkSent отмечает письмо как отправленное
+func (e *EmailQueueRepo) MarkSent(id int64) error {
+	query := "UPDATE email_queue SET sent_at = now(), attempts = attempts + 1, last_error = '' WHERE id = $1"
+	if _, err := e.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to mark email as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed записывает ошибку отправки и время следующей попытки
+func (e *EmailQueueRepo) MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	query := "UPDATE email_queue SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3 WHERE id = $1"
+	if _, err := e.db.Exec(query, id, lastError, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to mark email as failed: %w", err)
+	}
+	return nil
+}
+
+// Get получает письмо по ID
+func (e *EmailQueueRepo) Get(id int64) (models.EmailQueue, error) {
+	query := "SELECT " + emailQueueColumns + " FROM email_queue WHERE id = $1"
+
+	email, err := scanEmailQueue(e.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return models.EmailQueue{}, fmt.Errorf("email with id %d not found", id)
+	}
+	if err != nil {
+		return models.EmailQueue{}, fmt.Errorf("failed to get email: %w", err)
+	}
+
+	return email, nil
+}
+
+func scanEmailQueue(row rowScanner) (models.EmailQueue, error) {
+	var email models.EmailQueue
+	err := row.Scan(
+		&email.ID,
+		&email.Recipient,
+		&email.Subject,
+		&email.Body,
+		&email.Attempts,
+		&email.LastError,
+		&email.NextAttemptAt,
+		&email.SentAt,
+		&email.CreatedAt,
+	)
+	return email, err
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailQueueRepo_EnqueueAndDue(t *testing.T) {
+	cleanupTable(t, "email_queue")
+	repo := NewEmailQueueRepo(testDB)
+
+	first, err := repo.Enqueue(models.EmailQueue{Recipient: "owner@example.com", Subject: "first", Body: "body"})
+	require.NoError(t, err)
+	assert.NotZero(t, first.ID)
+	assert.Equal(t, int32(0), first.Attempts)
+	assert.Nil(t, first.SentAt)
+
+	second, err := repo.Enqueue(models.EmailQueue{Recipient: "owner@example.com", Subject: "second", Body: "body"})
+	require.NoError(t, err)
+
+	due, err := repo.Due(10, 5, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, first.ID, due[0].ID)
+
+	// Захваченные письма не выдаются повторно до истечения lease
+	due, err = repo.Due(10, 5, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	require.NoError(t, repo.MarkSent(first.ID))
+	sent, err := repo.Get(first.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, sent.SentAt)
+	assert.Equal(t, int32(1), sent.Attempts)
+
+	require.NoError(t, repo.MarkFailed(second.ID, "connection refused", time.Now().Add(-time.Second)))
+	due, err = repo.Due(10, 5, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, second.ID, due[0].ID)
+	assert.Equal(t, "connection refused", due[0].LastError)
+	assert.Equal(t, int32(1), due[0].Attempts)
+
+	// После maxAttempts попыток письмо больше не выдается
+	require.NoError(t, repo.MarkFailed(second.ID, "connection refused", time.Now().Add(-time.Second)))
+	due, err = repo.Due(10, 2, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+)
+
+// FeedbackRepo репозиторий для работы с таблицей feedback
+type FeedbackRepo struct {
+	*entityreqdecorator.Repository[models.Feedback]
+	db *sql.DB
+}
+
+// NewFeedbackRepo создает новый экземпляр репозитория обратной связи
+func NewFeedbackRepo(db *sql.DB) *FeedbackRepo {
+	return &FeedbackRepo{
+		Repository: entityreqdecorator.NewRepository(db, "feedback", "feedback", feedbackSchema, feedbackColumns),
+		db:         db,
+	}
+}
+
+// Create сохраняет новое сообщение
+func (f *FeedbackRepo) Create(feedback models.Feedback) (models.Feedback, error) {
+	query := `
+		INSERT INTO feedback (author_name, contact, message, status, source_ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, author_name, contact, message, created_at, status, source_ip, user_agent
+	`
+
+	var created models.Feedback
+	err := f.db.QueryRow(query,
+		feedback.AuthorName,
+		feedback.Contact,
+		feedback.Message,
+		feedback.Status,
+		feedback.SourceIp,
+		feedback.UserAgent,
+	).Scan(
+		&created.ID,
+		&created.AuthorName,
+		&created.Contact,
+		&created.Message,
+		&created.CreatedAt,
+		&created.Status,
+		&created.SourceIp,
+		&created.UserAgent,
+	)
+
+	if err != nil {
+		return models.Feedback{}, fmt.Errorf("failed to create feedback: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateStatus меняет статус сообщений из списка, которые сейчас находятся в одном из статусов from.
+// Возвращает ID обновленных сообщений.
+func (f *FeedbackRepo) UpdateStatus(ids []int64, status string, from []string) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := "UPDATE feedback SET status = $1 WHERE id = ANY($2) AND status = ANY($3) RETURNING id"
+	rows, err := f.db.Query(query, status, pq.Array(ids), pq.Array(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update feedback status: %w", err)
+	}
+	defer rows.Close()
+
+	var updatedIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan updated feedback ID: %w", err)
+		}
+		updatedIDs = append(updatedIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return updatedIDs, nil
+}
+
+// CountByStatus возвращает количество сообщений в статусе
+func (f *FeedbackRepo) CountByStatus(status string) (int, error) {
+	var count int
+	err := f.db.QueryRow("SELECT COUNT(*) FROM feedback WHERE status = $1", status).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count feedback by status: %w", err)
+	}
+	return count, nil
+}
+
+// feedbackSchema колонки обратной связи, доступные в запросах списка
+var feedbackSchema = entityreqdecorator.Schema{
+	{Name: "id", Type: entityreqdecorator.TypeInt, Filterable: true, Sortable: true},
+	{Name: "author_name", Alias: "authorName", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true},
+	{Name: "contact", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true},
+	{Name: "created_at", Alias: "createdAt", Type: entityreqdecorator.TypeTimestamp, Filterable: true, Sortable: true},
+	{Name: "status", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true, Operators: []string{"eq", "ne", "in", "nin"}},
+	{Name: "source_ip", Alias: "sourceIp", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true},
+	{Name: "message", Type: entityreqdecorator.TypeText},
+	{Name: "user_agent", Alias: "userAgent", Type: entityreqdecorator.TypeText},
+}
+
+// feedbackColumns сопоставляет колонки с полями модели для сканирования выбранных колонок
+func feedbackColumns(f *models.Feedback) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          &f.ID,
+		"author_name": &f.AuthorName,
+		"contact":     &f.Contact,
+		"message":     &f.Message,
+		"created_at":  &f.CreatedAt,
+		"status":      &f.Status,
+		"source_ip":   &f.SourceIp,
+		"user_agent":  &f.UserAgent,
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"testing"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedbackRepo_CreateGet(t *testing.T) {
+	cleanupTable(t, "feedback")
+	repo := NewFeedbackRepo(testDB)
+
+	created, err := repo.Create(models.Feedback{
+		AuthorName: "Иван",
+		Contact:    "ivan@example.com",
+		Message:    "Здравствуйте! Есть вакансия.",
+		Status:     "new",
+		SourceIp:   "10.0.0.1",
+		UserAgent:  "curl/8.0",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.Equal(t, "new", created.Status)
+
+	got, err := repo.Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.AuthorName, got.AuthorName)
+	assert.Equal(t, created.Contact, got.Contact)
+	assert.Equal(t, created.Message, got.Message)
+	assert.Equal(t, "10.0.0.1", got.SourceIp)
+	assert.Equal(t, "curl/8.0", got.UserAgent)
+
+	_, err = repo.Get(99999)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestFeedbackRepo_List(t *testing.T) {
+	cleanupTable(t, "feedback")
+	repo := NewFeedbackRepo(testDB)
+
+	for _, f := range []models.Feedback{
+		{AuthorName: "A", Contact: "a@example.com", Message: "first", Status: "new"},
+		{AuthorName: "B", Contact: "b@example.com", Message: "second", Status: "read"},
+		{AuthorName: "C", Contact: "c@example.com", Message: "third", Status: "new"},
+	} {
+		_, err := repo.Create(f)
+		require.NoError(t, err)
+	}
+
+	result, err := repo.List(entityreqdecorator.PagebleRq{
+		Page: 1,
+		Size: 10,
+		Sort: []entityreqdecorator.SortBy{{Field: "id", Order: "DESC"}},
+		Filter: map[string]entityreqdecorator.SQLGenerator{
+			"status": &entityreqdecorator.PredicateEQ{
+				Predicate: entityreqdecorator.Predicate{Value: "new"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, "C", result.Content[0].AuthorName)
+	assert.Equal(t, "A", result.Content[1].AuthorName)
+}
+
+func TestFeedbackRepo_UpdateStatusAndCount(t *testing.T) {
+	cleanupTable(t, "feedback")
+	repo := NewFeedbackRepo(testDB)
+
+	var ids []int64
+	for _, status := range []string{"new", "new", "replied"} {
+		created, err := repo.Create(models.Feedback{AuthorName: "A", Contact: "a@example.com", Message: "hi", Status: status})
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+
+	count, err := repo.CountByStatus("new")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Сообщение в статусе replied не входит в from и не должно измениться
+	updated, err := repo.UpdateStatus(ids, "read", []string{"new"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ids[:2], updated)
+
+	got, err := repo.Get(ids[2])
+	require.NoError(t, err)
+	assert.Equal(t, "replied", got.Status)
+
+	count, err = repo.CountByStatus("new")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+const fileColumns = "id, key, url, content_type, size, keys, urls, created_at"
+
+// fileUnreferenced условие для files f: ни один URL файла не используется
+// в логотипах технологий и мест работы
+const fileUnreferenced = `
+	NOT EXISTS (SELECT 1 FROM technology t WHERE t.logo_url = ANY(f.urls))
+	AND NOT EXISTS (
+		SELECT 1 FROM work_history w,
+			jsonb_each_text(CASE WHEN jsonb_typeof(w.logo_url) = 'object' THEN w.logo_url ELSE '{}'::jsonb END) v
+		WHERE v.value = ANY(f.urls)
+	)`
+
+// FileRepo репозиторий метаданных загруженных файлов
+type FileRepo struct {
+	db *sql.DB
+}
+
+// NewFileRepo создает новый экземпляр репозитория файлов
+func NewFileRepo(db *sql.DB) *FileRepo {
+	return &FileRepo{
+		db: db,
+	}
+}
+
+// Create сохраняет метаданные файла. Повторная загрузка того же файла
+// обновляет created_at, чтобы он снова получил льготный период до удаления.
+func (f *FileRepo) Create(file models.File) (models.File, error) {
+	query := `
+		INSERT INTO files (key, url, content_type, size, keys, urls)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE SET
+			url = EXCLUDED.url, keys = EXCLUDED.keys, urls = EXCLUDED.urls, created_at = now()
+		RETURNING ` + fileColumns
+
+	created, err := scanFile(f.db.QueryRow(query,
+		file.Key,
+		file.Url,
+		file.ContentType,
+		file.Size,
+		pq.Array(file.Keys),
+		pq.Array(file.Urls),
+	))
+	if err != nil {
+		return models.File{}, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	return created, nil
+}
+
+// Get получает метаданные файла по ID
+func (f *FileRepo) Get(id int64) (models.File, error) {
+	query := "SELECT " + fileColumns + " FROM files WHERE id = $1"
+
+	file, err := scanFile(f.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return models.File{}, fmt.Errorf("file with id %d not found", id)
+	}
+	if err != nil {
+		return models.File{}, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Orphaned возвращает до limit неиспользуемых файлов, загруженных раньше createdBefore
+func (f *FileRepo) Orphaned(createdBefore time.Time, limit int) ([]models.File, error) {
+	query := "SELECT " + fileColumns + " FROM files f WHERE f.created_at < $1 AND " + fileUnreferenced + `
+		ORDER BY f.id
+		LIMIT $2`
+
+	rows, err := f.db.Query(query, createdBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphaned files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []models.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file: %w", err)
+		}
+		files = append(files, file)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return files, nil
+}
+
+// DeleteOrphaned удаляет метаданные файла, если он все еще не используется и загружен
+// раньше createdBefore. Возвращает false, если файл успели использовать или загрузить заново.
+func (f *FileRepo) DeleteOrphaned(id int64, createdBefore time.Time) (bool, error) {
+	query := "DELETE FROM files f WHERE f.id = $1 AND f.created_at < $2 AND " + fileUnreferenced + " RETURNING f.id"
+
+	var deletedID int64
+	err := f.db.QueryRow(query, id, createdBefore).Scan(&deletedID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to delete orphaned file: %w", err)
+	}
+
+	return true, nil
+}
+
+func scanFile(row rowScanner) (models.File, error) {
+	var file models.File
+	err := row.Scan(
+		&file.ID,
+		&file.Key,
+		&file.Url,
+		&file.ContentType,
+		&file.Size,
+		pq.Array(&file.Keys),
+		pq.Array(&file.Urls),
+		&file.CreatedAt,
+	)
+	return file, err
+}
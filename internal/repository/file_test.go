@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFile(name string) models.File {
+	return models.File{
+		Key:         name + ".png",
+		Url:         "/files/" + name + ".png",
+		ContentType: "image/png",
+		Size:        100,
+		Keys:        []string{name + ".png", name + "_32.png"},
+		Urls:        []string{"/files/" + name + ".png", "/files/" + name + "_32.png"},
+	}
+}
+
+func TestFileRepo_Create(t *testing.T) {
+	cleanupTable(t, "files")
+	repo := NewFileRepo(testDB)
+
+	created, err := repo.Create(newTestFile("logo"))
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, []string{"logo.png", "logo_32.png"}, created.Keys)
+	assert.Equal(t, []string{"/files/logo.png", "/files/logo_32.png"}, created.Urls)
+
+	// Повторная загрузка того же файла не создает дубликат
+	again, err := repo.Create(newTestFile("logo"))
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, again.ID)
+	assert.False(t, again.CreatedAt.Before(created.CreatedAt))
+
+	got, err := repo.Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Key, got.Key)
+
+	_, err = repo.Get(99999)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestFileRepo_Orphaned(t *testing.T) {
+	cleanupAllTables(t)
+	repo := NewFileRepo(testDB)
+	techRepo := NewTechnologyRepo(testDB)
+	whRepo := NewWorkHistoryRepo(testDB)
+
+	orphan, err := repo.Create(newTestFile("orphan"))
+	require.NoError(t, err)
+	techFile, err := repo.Create(newTestFile("tech"))
+	require.NoError(t, err)
+	whFile, err := repo.Create(newTestFile("wh"))
+	require.NoError(t, err)
+
+	_, err = techRepo.Create(models.Technology{Title: "Go", LogoUrl: newPgText("/files/tech.png")})
+	require.NoError(t, err)
+	_, err = whRepo.Create(models.WorkHistory{
+		Name:        "Company",
+		About:       "About",
+		LogoUrl:     &types.LogoVariants{Original: "/files/other.png", Sm: "/files/wh_32.png"},
+		PeriodStart: newPgDate(2020, time.January, 1),
+	}, nil)
+	require.NoError(t, err)
+
+	// Файлы младше льготного периода не считаются брошенными
+	files, err := repo.Orphaned(time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	files, err = repo.Orphaned(time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, orphan.ID, files[0].ID)
+
+	deleted, err := repo.DeleteOrphaned(techFile.ID, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, deleted, "используемый файл не должен удаляться")
+
+	deleted, err = repo.DeleteOrphaned(whFile.ID, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, deleted, "файл, на уменьшенную копию которого ссылается место работы, не должен удаляться")
+
+	deleted, err = repo.DeleteOrphaned(orphan.ID, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = repo.Get(orphan.ID)
+	require.Error(t, err)
+}
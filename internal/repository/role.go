@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// RoleRepo репозиторий для работы с таблицами roles и role_permissions
+type RoleRepo struct {
+	db *sql.DB
+}
+
+// NewRoleRepo создает новый экземпляр репозитория ролей
+func NewRoleRepo(db *sql.DB) *RoleRepo {
+	return &RoleRepo{
+		db: db,
+	}
+}
+
+// Get получает роль по имени
+func (r *RoleRepo) Get(name string) (models.Role, error) {
+	query := "SELECT name, title FROM roles WHERE name = $1"
+
+	var role models.Role
+	err := r.db.QueryRow(query, name).Scan(&role.Name, &role.Title)
+
+	if err == sql.ErrNoRows {
+		return models.Role{}, fmt.Errorf("role %q not found", name)
+	}
+	if err != nil {
+		return models.Role{}, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+// Permissions получает список прав роли
+func (r *RoleRepo) Permissions(role string) ([]string, error) {
+	query := "SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission"
+	rows, err := r.db.Query(query, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return permissions, nil
+}
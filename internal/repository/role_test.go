@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleRepo_Get(t *testing.T) {
+	repo := NewRoleRepo(testDB)
+
+	role, err := repo.Get("editor")
+	require.NoError(t, err)
+	assert.Equal(t, "editor", role.Name)
+	assert.Equal(t, "Редактор", role.Title)
+
+	_, err = repo.Get("unknown")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestRoleRepo_Permissions(t *testing.T) {
+	repo := NewRoleRepo(testDB)
+
+	permissions, err := repo.Permissions("editor")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"edu:write", "wh:write"}, permissions)
+
+	permissions, err = repo.Permissions("viewer")
+	require.NoError(t, err)
+	assert.Empty(t, permissions)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
+)
+
+// searchHeadlineOptions параметры ts_headline: до двух фрагментов, найденные слова в <mark>
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+
+// searchQuery ищет по колонкам search_vector (миграция 0012_add_search) технологий,
+// истории работы и образования. Запрос разбирается websearch_to_tsquery: поддерживаются
+// "фразы в кавычках", OR и -исключение.
+const searchQuery = `
+	WITH q AS (SELECT websearch_to_tsquery('russian', $1) AS query)
+	SELECT 'technology', t.id, t.title,
+		ts_headline('russian', t.title || '. ' || coalesce(t.description, ''), q.query, $3),
+		ts_rank(t.search_vector, q.query) AS rank
+	FROM technology t, q
+	WHERE t.search_vector @@ q.query
+	UNION ALL
+	SELECT 'workHistory', w.id, w.name,
+		ts_headline('russian', w.name || '. ' || w.about || ' ' || search_array_text(w.what_i_did) || ' ' || search_array_text(w.projects), q.query, $3),
+		ts_rank(w.search_vector, q.query)
+	FROM work_history w, q
+	WHERE w.search_vector @@ q.query
+	UNION ALL
+	SELECT 'education', e.id, e.course,
+		ts_headline('russian', e.course || '. ' || e.organization, q.query, $3),
+		ts_rank(e.search_vector, q.query)
+	FROM education e, q
+	WHERE e.search_vector @@ q.query
+	ORDER BY rank DESC, id
+	LIMIT $2
+`
+
+// SearchRepo репозиторий полнотекстового поиска по резюме
+type SearchRepo struct {
+	db *sql.DB
+}
+
+// NewSearchRepo создает новый экземпляр репозитория поиска
+func NewSearchRepo(db *sql.DB) *SearchRepo {
+	return &SearchRepo{
+		db: db,
+	}
+}
+
+// Search возвращает до limit записей, подходящих под запрос, по убыванию релевантности
+func (s *SearchRepo) Search(query string, limit int) ([]types.SearchHit, error) {
+	rows, err := s.db.Query(searchQuery, query, limit, searchHeadlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []types.SearchHit{}
+	for rows.Next() {
+		var hit types.SearchHit
+		if err := rows.Scan(&hit.Type, &hit.ID, &hit.Title, &hit.Snippet, &hit.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+func TestSearchRepo_Search(t *testing.T) {
+	cleanupTable(t, "technology")
+	cleanupTable(t, "work_history")
+	cleanupTable(t, "education")
+
+	tech, err := NewTechnologyRepo(testDB).Create(models.Technology{
+		Title:       "PostgreSQL",
+		Description: pgtype.Text{String: "Реляционная база данных, полнотекстовый поиск", Valid: true},
+	})
+	require.NoError(t, err)
+	wh, err := NewWorkHistoryRepo(testDB).Create(models.WorkHistory{
+		Name:        "Acme",
+		About:       "Разрабатывал платежные сервисы",
+		PeriodStart: newPgDate(2020, time.January, 1),
+		WhatIDid:    []string{"Developed the search API"},
+		Projects:    []string{},
+	}, nil)
+	require.NoError(t, err)
+	_, err = NewEducationRepo(testDB).Create(models.Education{
+		Year:         2015,
+		Course:       "Базы данных",
+		Organization: "МГУ",
+	})
+	require.NoError(t, err)
+
+	repo := NewSearchRepo(testDB)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantTypes []string
+	}{
+		{
+			name:      "русский стемминг",
+			query:     "разрабатывать",
+			wantTypes: []string{"workHistory"},
+		},
+		{
+			name:      "английский стемминг",
+			query:     "developing",
+			wantTypes: []string{"workHistory"},
+		},
+		{
+			name:      "несколько типов, заголовок выше описания",
+			query:     "база данных",
+			wantTypes: []string{"education", "technology"},
+		},
+		{
+			name:      "нет совпадений",
+			query:     "kubernetes",
+			wantTypes: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := repo.Search(tt.query, 10)
+			require.NoError(t, err)
+
+			types := []string{}
+			for _, hit := range hits {
+				types = append(types, hit.Type)
+			}
+			assert.Equal(t, tt.wantTypes, types)
+		})
+	}
+
+	hits, err := repo.Search("поиск", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, tech.ID, hits[0].ID)
+	assert.Equal(t, "PostgreSQL", hits[0].Title)
+	assert.Contains(t, hits[0].Snippet, "<mark>поиск</mark>")
+
+	hits, err = repo.Search("acme", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, wh.ID, hits[0].ID)
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// SessionRepo репозиторий для работы с таблицей sessions
+type SessionRepo struct {
+	db *sql.DB
+}
+
+// NewSessionRepo создает новый экземпляр репозитория сессий
+func NewSessionRepo(db *sql.DB) *SessionRepo {
+	return &SessionRepo{
+		db: db,
+	}
+}
+
+// Get получает сессию по ID (хешу токена)
+func (s *SessionRepo) Get(id string) (models.Session, error) {
+	query := "SELECT id, user_id, created_at, expires_at FROM sessions WHERE id = $1"
+
+	var session models.Session
+	err := s.db.QueryRow(query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return models.Session{}, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return models.Session{}, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// Create сохраняет новую сессию
+func (s *SessionRepo) Create(session models.Session) (models.Session, error) {
+	query := `
+		INSERT INTO sessions (id, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, created_at, expires_at
+	`
+
+	var created models.Session
+	err := s.db.QueryRow(query, session.ID, session.UserID, session.ExpiresAt).Scan(
+		&created.ID,
+		&created.UserID,
+		&created.CreatedAt,
+		&created.ExpiresAt,
+	)
+
+	if err != nil {
+		return models.Session{}, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return created, nil
+}
+
+// Delete удаляет сессию по ID
+func (s *SessionRepo) Delete(id string) error {
+	query := "DELETE FROM sessions WHERE id = $1"
+	if _, err := s.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет все просроченные сессии и возвращает их количество
+func (s *SessionRepo) DeleteExpired() (int64, error) {
+	query := "DELETE FROM sessions WHERE expires_at <= now()"
+	result, err := s.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
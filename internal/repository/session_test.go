@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRepo_CreateGetDelete(t *testing.T) {
+	cleanupTable(t, "sessions")
+	cleanupTable(t, "users")
+	userRepo := NewUserRepo(testDB)
+	repo := NewSessionRepo(testDB)
+
+	user, err := userRepo.Create(models.User{Username: "admin", PasswordHash: "hash", Role: "owner"})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	created, err := repo.Create(models.Session{
+		ID:        "session-hash",
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "session-hash", created.ID)
+	assert.Equal(t, user.ID, created.UserID)
+	assert.True(t, expiresAt.Equal(created.ExpiresAt))
+
+	got, err := repo.Get("session-hash")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.UserID)
+
+	require.NoError(t, repo.Delete("session-hash"))
+
+	_, err = repo.Get("session-hash")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestSessionRepo_DeleteExpired(t *testing.T) {
+	cleanupTable(t, "sessions")
+	cleanupTable(t, "users")
+	userRepo := NewUserRepo(testDB)
+	repo := NewSessionRepo(testDB)
+
+	user, err := userRepo.Create(models.User{Username: "admin", PasswordHash: "hash", Role: "owner"})
+	require.NoError(t, err)
+
+	_, err = repo.Create(models.Session{ID: "expired", UserID: user.ID, ExpiresAt: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	_, err = repo.Create(models.Session{ID: "active", UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteExpired()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.Get("active")
+	require.NoError(t, err)
+}
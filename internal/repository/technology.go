@@ -4,134 +4,24 @@ import (
 	"database/sql"
 	"fmt"
 
-	"github.com/lib/pq"
-
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 )
 
-
-
-
 type TechnologyRepo struct {
-	db *sql.DB
+	*entityreqdecorator.Repository[models.Technology]
+	tags *entityreqdecorator.Repository[models.Tag]
+	db   *sql.DB
 }
 
 func NewTechnologyRepo(db *sql.DB) *TechnologyRepo {
 	return &TechnologyRepo{
-		db: db,
-	}
-}
-// DeleteList удаляет список технологий по ID
-func (t *TechnologyRepo) DeleteList(ids []int64) ([]int64, error) {
-	if len(ids) == 0 {
-		return nil, nil
-	}
-
-	query := "DELETE FROM technology WHERE id = ANY($1) RETURNING id"
-	rows, err := t.db.Query(query, pq.Array(ids))
-	if err != nil {
-		return nil, fmt.Errorf("failed to delete technology list: %w", err)
-	}
-	defer rows.Close()
-
-	var deletedIDs []int64
-	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("failed to scan deleted technology ID: %w", err)
-		}
-		deletedIDs = append(deletedIDs, id)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
-	}
-
-	return deletedIDs, nil
-}
-
-// Delete удаляет одну технологию по ID
-func (t *TechnologyRepo) Delete(id int64) (int64, error) {
-	query := "DELETE FROM technology WHERE id = $1"
-	result, err := t.db.Exec(query, id)
-	if err != nil {
-		return 0, fmt.Errorf("failed to delete technology: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return 0, fmt.Errorf("technology with id %d not found", id)
-	}
-
-	return id, nil
-}
-
-// Get получает одну технологию по ID
-func (t *TechnologyRepo) Get(id int64) (models.Technology, error) {
-	query := "SELECT id, title, description, logo_url FROM technology WHERE id = $1"
-	
-	var technology models.Technology
-	err := t.db.QueryRow(query, id).Scan(
-		&technology.ID,
-		&technology.Title,
-		&technology.Description,
-		&technology.LogoUrl,
-	)
-	
-	if err == sql.ErrNoRows {
-		return models.Technology{}, fmt.Errorf("technology with id %d not found", id)
-	}
-	if err != nil {
-		return models.Technology{}, fmt.Errorf("failed to get technology: %w", err)
+		Repository: entityreqdecorator.NewRepository(db, "technology", "technology", technologySchema, technologyColumns),
+		tags:       entityreqdecorator.NewRepository(db, "tag", "tag", tagSchema, tagColumns),
+		db:         db,
 	}
-
-	return technology, nil
 }
-func (t *TechnologyRepo) List(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Technology], error) {
-	baseQuery := "SELECT id, title, description, logo_url FROM technology"
-
-	queryParams := entityreqdecorator.BuildListQuery(
-		req, baseQuery, t.isValidField,
-	)
-
-	var total int
-	err := t.db.QueryRow(queryParams.CountQuery, queryParams.CountParams...).Scan(&total)
-	if err != nil {
-		return entityreqdecorator.PagebleRs[models.Technology]{}, fmt.Errorf("failed to count technologies: %w", err)
-	}
 
-	rows, err := t.db.Query(queryParams.SelectQuery, queryParams.SelectParams...)
-	if err != nil {
-		return entityreqdecorator.PagebleRs[models.Technology]{}, fmt.Errorf("failed to query technologies: %w", err)
-	}
-	defer rows.Close()
-
-	var technologies []models.Technology
-	for rows.Next() {
-		var technology models.Technology
-		err := rows.Scan(&technology.ID, &technology.Title, &technology.Description,  &technology.LogoUrl)
-		if err != nil {
-			return entityreqdecorator.PagebleRs[models.Technology]{}, fmt.Errorf("failed to scan technology: %w", err)
-		}
-		technologies = append(technologies, technology)
-	}
-
-	if err = rows.Err(); err != nil {
-		return entityreqdecorator.PagebleRs[models.Technology]{}, fmt.Errorf("rows error: %w", err)
-	}
-	return entityreqdecorator.PagebleRs[models.Technology]{
-		Total:   total,
-		Content: technologies,
-		Page:    req.Page,
-		Size:    req.Size,
-		Sort:    req.Sort,
-	}, nil
-}
 // Create создает новую технологию
 func (t *TechnologyRepo) Create(technology models.Technology) (models.Technology, error) {
 	query := `
@@ -193,13 +83,56 @@ func (t *TechnologyRepo) Update(technology models.Technology) (models.Technology
 	return updated, nil
 }
 
-// isValidField проверяет, является ли поле валидным для сортировки и фильтрации
-func (t *TechnologyRepo) isValidField(field string) bool {
-	validFields := map[string]bool{
-		"id":          true,
-		"title":       true,
-		"description": true,
-		"logo_url":    true,
+// Tags получает теги технологии
+func (t *TechnologyRepo) Tags(techID int64) ([]models.Tag, error) {
+	return t.tags.Related(technologyTags, techID)
+}
+
+// SetTags заменяет теги технологии на tagIDs и возвращает новый список тегов
+func (t *TechnologyRepo) SetTags(techID int64, tagIDs []int64) ([]models.Tag, error) {
+	if err := technologyTags.Set(t.db, techID, tagIDs); err != nil {
+		return nil, err
+	}
+	return t.Tags(techID)
+}
+
+// TagsByTechnology получает теги сразу нескольких технологий одним запросом
+func (t *TechnologyRepo) TagsByTechnology(techIDs []int64) (map[int64][]models.Tag, error) {
+	return t.tags.RelatedByOwner(technologyTags, techIDs)
+}
+
+// technologyTags связь технологий с тегами
+var technologyTags = entityreqdecorator.Relation{
+	Table:        "technologies_tag",
+	OwnerTable:   "technology",
+	OwnerColumn:  "technology_id",
+	OwnerName:    "technology",
+	TargetTable:  "tag",
+	TargetColumn: "tag_id",
+	TargetName:   "tag",
+}
+
+// technologySchema колонки технологий, доступные в запросах списка
+var technologySchema = entityreqdecorator.Schema{
+	{Name: "id", Type: entityreqdecorator.TypeInt, Filterable: true, Sortable: true},
+	{Name: "title", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true},
+	{Name: "description", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true, Nullable: true},
+	{Name: "logo_url", Alias: "logoUrl", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true, Nullable: true},
+	// tag=backend: технологии с тегом backend
+	{Name: "tag", Type: entityreqdecorator.TypeText, Filterable: true, IgnoreCase: true, Operators: relationOperators,
+		Relation: &entityreqdecorator.RelationFilter{Relation: technologyTags, Column: "name"}},
+}
+
+// relationOperators операторы фильтров по связанным записям. Отрицания не поддерживаются:
+// ne в EXISTS означало бы «есть хотя бы одна другая связь».
+var relationOperators = []string{"eq", "in", "ilike", "startswith"}
+
+// technologyColumns сопоставляет колонки с полями модели для сканирования выбранных колонок
+func technologyColumns(t *models.Technology) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          &t.ID,
+		"title":       &t.Title,
+		"description": &t.Description,
+		"logo_url":    &t.LogoUrl,
 	}
-	return validFields[field]
 }
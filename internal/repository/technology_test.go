@@ -421,3 +421,111 @@ func TestTechnologyRepo_List_Sorting(t *testing.T) {
 	assert.Equal(t, "Middle", result.Content[1].Title)
 	assert.Equal(t, "Alpha", result.Content[2].Title)
 }
+
+func TestTechnologyRepo_SetTags(t *testing.T) {
+	cleanupAllTables(t)
+	repo := NewTechnologyRepo(testDB)
+	tagRepo := NewTagRepo(testDB)
+
+	tech, err := repo.Create(models.Technology{Title: "Go"})
+	require.NoError(t, err)
+	var tagIDs []int64
+	for _, name := range []string{"Backend", "Cloud", "Databases"} {
+		tag, err := tagRepo.Create(models.Tag{Name: name, HexColor: "#ffffff"})
+		require.NoError(t, err)
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	// Первый набор, затем замена: один тег удаляется, один добавляется, дубликаты игнорируются
+	tags, err := repo.SetTags(tech.ID, []int64{tagIDs[0], tagIDs[1]})
+	require.NoError(t, err)
+	assert.Len(t, tags, 2)
+
+	tags, err = repo.SetTags(tech.ID, []int64{tagIDs[2], tagIDs[1], tagIDs[2]})
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, tagIDs[1], tags[0].ID)
+	assert.Equal(t, tagIDs[2], tags[1].ID)
+
+	technologies, err := tagRepo.Technologies(tagIDs[2])
+	require.NoError(t, err)
+	require.Len(t, technologies, 1)
+	assert.Equal(t, tech.ID, technologies[0].ID)
+
+	t.Run("несуществующий тег", func(t *testing.T) {
+		_, err := repo.SetTags(tech.ID, []int64{tagIDs[0], 99999})
+		var missing *entityreqdecorator.MissingError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, []int64{99999}, missing.IDs)
+
+		// Связи не изменились
+		tags, err := repo.Tags(tech.ID)
+		require.NoError(t, err)
+		assert.Len(t, tags, 2)
+	})
+
+	t.Run("несуществующая технология", func(t *testing.T) {
+		_, err := repo.SetTags(99999, []int64{tagIDs[0]})
+		require.ErrorIs(t, err, entityreqdecorator.ErrNotFound)
+
+		_, err = tagRepo.Technologies(99999)
+		require.ErrorIs(t, err, entityreqdecorator.ErrNotFound)
+	})
+
+	t.Run("пустой список снимает все теги", func(t *testing.T) {
+		tags, err := repo.SetTags(tech.ID, []int64{})
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+}
+
+func TestTechnologyRepo_List_FilterByTag(t *testing.T) {
+	cleanupAllTables(t)
+	repo := NewTechnologyRepo(testDB)
+	tagRepo := NewTagRepo(testDB)
+
+	backend, err := tagRepo.Create(models.Tag{Name: "backend", HexColor: "#ffffff"})
+	require.NoError(t, err)
+	frontend, err := tagRepo.Create(models.Tag{Name: "frontend", HexColor: "#000000"})
+	require.NoError(t, err)
+
+	goTech, err := repo.Create(models.Technology{Title: "Go"})
+	require.NoError(t, err)
+	_, err = repo.SetTags(goTech.ID, []int64{backend.ID})
+	require.NoError(t, err)
+	gql, err := repo.Create(models.Technology{Title: "GraphQL"})
+	require.NoError(t, err)
+	_, err = repo.SetTags(gql.ID, []int64{backend.ID, frontend.ID})
+	require.NoError(t, err)
+	react, err := repo.Create(models.Technology{Title: "React"})
+	require.NoError(t, err)
+	_, err = repo.SetTags(react.ID, []int64{frontend.ID})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   map[string][]string
+		wantIDs []int64
+	}{
+		{name: "по тегу без учета регистра", query: map[string][]string{"tag": {"Backend"}}, wantIDs: []int64{goTech.ID, gql.ID}},
+		{name: "вместе с фильтром по колонке", query: map[string][]string{"tag": {"backend"}, "title": {"startswith(gr)"}}, wantIDs: []int64{gql.ID}},
+		{name: "по нескольким тегам", query: map[string][]string{"tag": {"in(backend,frontend)"}}, wantIDs: []int64{goTech.ID, gql.ID, react.ID}},
+		{name: "нет технологий с тегом", query: map[string][]string{"tag": {"mobile"}}, wantIDs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := entityreqdecorator.ParseQueryParamsStrict(tt.query)
+			require.NoError(t, err)
+			req.Sort = []entityreqdecorator.SortBy{{Field: "id", Order: "ASC"}}
+
+			result, err := repo.List(req)
+			require.NoError(t, err)
+			var ids []int64
+			for _, tech := range result.Content {
+				ids = append(ids, tech.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, len(tt.wantIDs), result.Total)
+		})
+	}
+}
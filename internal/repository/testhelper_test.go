@@ -112,7 +112,7 @@ func runMigrations(db *sql.DB) error {
 			id BIGSERIAL PRIMARY KEY,
 			name TEXT NOT NULL,
 			about TEXT NOT NULL,
-			logo_url BYTEA,
+			logo_url JSONB,
 			period_start DATE NOT NULL,
 			period_end DATE,
 			what_i_did TEXT[],
@@ -123,6 +123,103 @@ func runMigrations(db *sql.DB) error {
 			technology_id BIGINT NOT NULL REFERENCES technology (id) ON DELETE CASCADE,
 			PRIMARY KEY (work_history_id, technology_id)
 		)`,
+		// 0003_add_users.up.sql
+		`CREATE TABLE IF NOT EXISTS users (
+			id BIGSERIAL PRIMARY KEY,
+			username TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS sessions (
+			id TEXT PRIMARY KEY,
+			user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			expires_at TIMESTAMPTZ NOT NULL
+		)`,
+		// 0004_add_roles.up.sql
+		`CREATE TABLE IF NOT EXISTS roles (
+			name TEXT PRIMARY KEY,
+			title TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS role_permissions (
+			role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+			permission TEXT NOT NULL,
+			PRIMARY KEY (role, permission)
+		)`,
+		`INSERT INTO roles (name, title)
+		VALUES ('viewer', 'Наблюдатель'), ('editor', 'Редактор'), ('owner', 'Владелец')
+		ON CONFLICT (name) DO NOTHING`,
+		`INSERT INTO role_permissions (role, permission)
+		VALUES ('editor', 'wh:write'), ('editor', 'edu:write'), ('owner', 'tag:delete'), ('owner', 'wh:write')
+		ON CONFLICT DO NOTHING`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'viewer' REFERENCES roles (name)`,
+		// 0005_add_api_tokens.up.sql
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			scopes TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			expires_at TIMESTAMPTZ,
+			last_used_at TIMESTAMPTZ,
+			revoked_at TIMESTAMPTZ
+		)`,
+		// 0006_add_feedback.up.sql
+		`CREATE TABLE IF NOT EXISTS feedback (
+			id BIGSERIAL PRIMARY KEY,
+			author_name TEXT NOT NULL,
+			contact TEXT NOT NULL,
+			message TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			status TEXT NOT NULL DEFAULT 'new',
+			source_ip TEXT NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT ''
+		)`,
+		// 0008_add_email_queue.up.sql
+		`CREATE TABLE IF NOT EXISTS email_queue (
+			id BIGSERIAL PRIMARY KEY,
+			recipient TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			body TEXT NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			sent_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`,
+		// 0011_add_files.up.sql
+		`CREATE TABLE IF NOT EXISTS files (
+			id BIGSERIAL PRIMARY KEY,
+			key TEXT NOT NULL UNIQUE,
+			url TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size BIGINT NOT NULL,
+			keys TEXT[] NOT NULL DEFAULT '{}',
+			urls TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`,
+		// 0012_add_search.up.sql
+		`CREATE OR REPLACE FUNCTION search_array_text(arr TEXT[]) RETURNS TEXT
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE
+		AS $$ SELECT coalesce(array_to_string(arr, ' '), '') $$`,
+		`ALTER TABLE technology
+		ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('russian', title), 'A') ||
+			setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+		) STORED`,
+		`ALTER TABLE work_history
+		ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('russian', name), 'A') ||
+			setweight(to_tsvector('russian', about), 'B') ||
+			setweight(to_tsvector('russian', search_array_text(what_i_did)), 'C') ||
+			setweight(to_tsvector('russian', search_array_text(projects)), 'C')
+		) STORED`,
+		`ALTER TABLE education
+		ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('russian', course), 'A') ||
+			setweight(to_tsvector('russian', organization), 'B')
+		) STORED`,
 	}
 
 	for _, migration := range migrations {
@@ -152,6 +249,12 @@ func cleanupAllTables(t *testing.T) {
 	t.Helper()
 
 	tables := []string{
+		"files",
+		"email_queue",
+		"feedback",
+		"api_tokens",
+		"sessions",
+		"users",
 		"work_history_technology",
 		"technologies_tag",
 		"work_history",
@@ -168,7 +271,7 @@ func cleanupAllTables(t *testing.T) {
 	}
 
 	// Сбрасываем sequences
-	sequences := []string{"tag_id_seq", "education_id_seq", "technology_id_seq", "work_history_id_seq"}
+	sequences := []string{"tag_id_seq", "education_id_seq", "technology_id_seq", "work_history_id_seq", "users_id_seq", "api_tokens_id_seq", "feedback_id_seq", "email_queue_id_seq", "files_id_seq"}
 	for _, seq := range sequences {
 		_, _ = testDB.Exec(fmt.Sprintf("ALTER SEQUENCE %s RESTART WITH 1", seq))
 	}
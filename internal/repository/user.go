@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// UserRepo репозиторий для работы с таблицей users
+type UserRepo struct {
+	db *sql.DB
+}
+
+// NewUserRepo создает новый экземпляр репозитория пользователей
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{
+		db: db,
+	}
+}
+
+// Get получает пользователя по ID
+func (u *UserRepo) Get(id int64) (models.User, error) {
+	query := "SELECT id, username, password_hash, created_at, role FROM users WHERE id = $1"
+
+	var user models.User
+	err := u.db.QueryRow(query, id).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.Role,
+	)
+
+	if err == sql.ErrNoRows {
+		return models.User{}, fmt.Errorf("user with id %d not found", id)
+	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByUsername получает пользователя по логину
+func (u *UserRepo) GetByUsername(username string) (models.User, error) {
+	query := "SELECT id, username, password_hash, created_at, role FROM users WHERE username = $1"
+
+	var user models.User
+	err := u.db.QueryRow(query, username).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.Role,
+	)
+
+	if err == sql.ErrNoRows {
+		return models.User{}, fmt.Errorf("user %q not found", username)
+	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// Create создает нового пользователя
+func (u *UserRepo) Create(user models.User) (models.User, error) {
+	query := `
+		INSERT INTO users (username, password_hash, role)
+		VALUES ($1, $2, $3)
+		RETURNING id, username, password_hash, created_at, role
+	`
+
+	var created models.User
+	err := u.db.QueryRow(query, user.Username, user.PasswordHash, user.Role).Scan(
+		&created.ID,
+		&created.Username,
+		&created.PasswordHash,
+		&created.CreatedAt,
+		&created.Role,
+	)
+
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return created, nil
+}
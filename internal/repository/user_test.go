@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepo_Create(t *testing.T) {
+	cleanupTable(t, "users")
+	repo := NewUserRepo(testDB)
+
+	created, err := repo.Create(models.User{
+		Username:     "admin",
+		PasswordHash: "hash",
+		Role:         "editor",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "admin", created.Username)
+	assert.Equal(t, "hash", created.PasswordHash)
+	assert.Equal(t, "editor", created.Role)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	// Логин должен быть уникальным
+	_, err = repo.Create(models.User{
+		Username:     "admin",
+		PasswordHash: "other",
+		Role:         "editor",
+	})
+	require.Error(t, err, "должна быть ошибка при дублировании логина")
+}
+
+func TestUserRepo_Get(t *testing.T) {
+	cleanupTable(t, "users")
+	repo := NewUserRepo(testDB)
+
+	created, err := repo.Create(models.User{Username: "editor", PasswordHash: "hash", Role: "owner"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr bool
+	}{
+		{
+			name:    "получение существующего пользователя",
+			id:      created.ID,
+			wantErr: false,
+		},
+		{
+			name:    "получение несуществующего пользователя",
+			id:      99999,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Get(tt.id)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "not found")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, created.Username, got.Username)
+		})
+	}
+}
+
+func TestUserRepo_GetByUsername(t *testing.T) {
+	cleanupTable(t, "users")
+	repo := NewUserRepo(testDB)
+
+	created, err := repo.Create(models.User{Username: "viewer", PasswordHash: "hash", Role: "owner"})
+	require.NoError(t, err)
+
+	got, err := repo.GetByUsername("viewer")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+	assert.Equal(t, "hash", got.PasswordHash)
+
+	_, err = repo.GetByUsername("unknown")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -12,162 +12,42 @@ import (
 
 // WorkHistoryRepo репозиторий для работы с таблицей work_history
 type WorkHistoryRepo struct {
-	db *sql.DB
+	*entityreqdecorator.Repository[models.WorkHistory]
+	db           *sql.DB
+	technologies *entityreqdecorator.Repository[models.Technology]
+	tags         *entityreqdecorator.Repository[models.Tag]
 }
 
 // NewWorkHistoryRepo создает новый экземпляр репозитория истории работы
 func NewWorkHistoryRepo(db *sql.DB) *WorkHistoryRepo {
 	return &WorkHistoryRepo{
-		db: db,
+		Repository:   entityreqdecorator.NewRepository(db, "work_history", "work history", workHistorySchema, workHistoryColumns),
+		db:           db,
+		technologies: entityreqdecorator.NewRepository(db, "technology", "technology", technologySchema, technologyColumns),
+		tags:         entityreqdecorator.NewRepository(db, "tag", "tag", tagSchema, tagColumns),
 	}
 }
 
-// DeleteList удаляет список записей истории работы по ID
-func (w *WorkHistoryRepo) DeleteList(ids []int64) ([]int64, error) {
-	if len(ids) == 0 {
-		return nil, nil
-	}
-
-	query := "DELETE FROM work_history WHERE id = ANY($1) RETURNING id"
-	rows, err := w.db.Query(query, pq.Array(ids))
-	if err != nil {
-		return nil, fmt.Errorf("failed to delete work history list: %w", err)
-	}
-	defer rows.Close()
-
-	var deletedIDs []int64
-	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("failed to scan deleted work history ID: %w", err)
-		}
-		deletedIDs = append(deletedIDs, id)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
-	}
-
-	return deletedIDs, nil
-}
-
-// Delete удаляет одну запись истории работы по ID
-func (w *WorkHistoryRepo) Delete(id int64) (int64, error) {
-	query := "DELETE FROM work_history WHERE id = $1"
-	result, err := w.db.Exec(query, id)
-	if err != nil {
-		return 0, fmt.Errorf("failed to delete work history: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return 0, fmt.Errorf("work history with id %d not found", id)
-	}
-
-	return id, nil
-}
-
-// Get получает одну запись истории работы по ID
-func (w *WorkHistoryRepo) Get(id int64) (models.WorkHistory, error) {
-	query := `
-		SELECT id, name, about, logo_url, period_start, period_end, what_i_did, projects
-		FROM work_history
-		WHERE id = $1
-	`
-	
-	var workHistory models.WorkHistory
-	err := w.db.QueryRow(query, id).Scan(
-		&workHistory.ID,
-		&workHistory.Name,
-		&workHistory.About,
-		&workHistory.LogoUrl,
-		&workHistory.PeriodStart,
-		&workHistory.PeriodEnd,
-		pq.Array(&workHistory.WhatIDid),
-		pq.Array(&workHistory.Projects),
-	)
-	
-	if err == sql.ErrNoRows {
-		return models.WorkHistory{}, fmt.Errorf("work history with id %d not found", id)
-	}
+// Create создает новую запись истории работы и привязывает к ней технологии
+// technologyIDs в одной транзакции
+func (w *WorkHistoryRepo) Create(workHistory models.WorkHistory, technologyIDs []int64) (created models.WorkHistory, err error) {
+	tx, err := w.db.Begin()
 	if err != nil {
-		return models.WorkHistory{}, fmt.Errorf("failed to get work history: %w", err)
+		return models.WorkHistory{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-
-	return workHistory, nil
-}
-
-// List получает список записей истории работы с пагинацией, сортировкой и фильтрацией
-func (w *WorkHistoryRepo) List(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.WorkHistory], error) {
-	baseQuery := `
-		SELECT id, name, about, logo_url, period_start, period_end, what_i_did, projects
-		FROM work_history
-	`
-
-	queryParams := entityreqdecorator.BuildListQuery(
-		req, baseQuery, w.isValidField,
-	)
-
-	// Получаем общее количество записей
-	var total int
-	err := w.db.QueryRow(queryParams.CountQuery, queryParams.CountParams...).Scan(&total)
-	if err != nil {
-		return entityreqdecorator.PagebleRs[models.WorkHistory]{}, fmt.Errorf("failed to count work histories: %w", err)
-	}
-
-	// Получаем записи с учетом пагинации
-	rows, err := w.db.Query(queryParams.SelectQuery, queryParams.SelectParams...)
-	if err != nil {
-		return entityreqdecorator.PagebleRs[models.WorkHistory]{}, fmt.Errorf("failed to query work histories: %w", err)
-	}
-	defer rows.Close()
-
-	var workHistories []models.WorkHistory
-	for rows.Next() {
-		var workHistory models.WorkHistory
-		err := rows.Scan(
-			&workHistory.ID,
-			&workHistory.Name,
-			&workHistory.About,
-			&workHistory.LogoUrl,
-			&workHistory.PeriodStart,
-			&workHistory.PeriodEnd,
-			pq.Array(&workHistory.WhatIDid),
-			pq.Array(&workHistory.Projects),
-		)
+	defer func() {
 		if err != nil {
-			return entityreqdecorator.PagebleRs[models.WorkHistory]{}, fmt.Errorf("failed to scan work history: %w", err)
+			tx.Rollback()
 		}
-		workHistories = append(workHistories, workHistory)
-	}
-
-	if err = rows.Err(); err != nil {
-		return entityreqdecorator.PagebleRs[models.WorkHistory]{}, fmt.Errorf("rows error: %w", err)
-	}
-
-	return entityreqdecorator.PagebleRs[models.WorkHistory]{
-		Total:   total,
-		Content: workHistories,
-		Page:    req.Page,
-		Size:    req.Size,
-		Sort:    req.Sort,
-	}, nil
-}
+	}()
 
-// Create создает новую запись истории работы
-func (w *WorkHistoryRepo) Create(workHistory models.WorkHistory) (models.WorkHistory, error) {
 	query := `
 		INSERT INTO work_history (name, about, logo_url, period_start, period_end, what_i_did, projects)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, name, about, logo_url, period_start, period_end, what_i_did, projects
 	`
 
-	var created models.WorkHistory
-	err := w.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		workHistory.Name,
 		workHistory.About,
@@ -191,11 +71,31 @@ func (w *WorkHistoryRepo) Create(workHistory models.WorkHistory) (models.WorkHis
 		return models.WorkHistory{}, fmt.Errorf("failed to create work history: %w", err)
 	}
 
+	if len(technologyIDs) > 0 {
+		if err = workHistoryTechnologies.SetTx(tx, created.ID, technologyIDs); err != nil {
+			return models.WorkHistory{}, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return models.WorkHistory{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return created, nil
 }
 
-// Update обновляет существующую запись истории работы
-func (w *WorkHistoryRepo) Update(workHistory models.WorkHistory) (models.WorkHistory, error) {
+// Update обновляет существующую запись истории работы и в той же транзакции заменяет
+// ее технологии на technologyIDs. При technologyIDs == nil технологии не меняются.
+func (w *WorkHistoryRepo) Update(workHistory models.WorkHistory, technologyIDs []int64) (updated models.WorkHistory, err error) {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return models.WorkHistory{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
 	query := `
 		UPDATE work_history
 		SET name = $2, about = $3, logo_url = $4, period_start = $5, 
@@ -204,8 +104,7 @@ func (w *WorkHistoryRepo) Update(workHistory models.WorkHistory) (models.WorkHis
 		RETURNING id, name, about, logo_url, period_start, period_end, what_i_did, projects
 	`
 
-	var updated models.WorkHistory
-	err := w.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		workHistory.ID,
 		workHistory.Name,
@@ -227,23 +126,83 @@ func (w *WorkHistoryRepo) Update(workHistory models.WorkHistory) (models.WorkHis
 	)
 
 	if err == sql.ErrNoRows {
-		return models.WorkHistory{}, fmt.Errorf("work history with id %d not found", workHistory.ID)
+		return models.WorkHistory{}, fmt.Errorf("work history with id %d %w", workHistory.ID, entityreqdecorator.ErrNotFound)
 	}
 	if err != nil {
 		return models.WorkHistory{}, fmt.Errorf("failed to update work history: %w", err)
 	}
 
+	if technologyIDs != nil {
+		if err = workHistoryTechnologies.SetTx(tx, updated.ID, technologyIDs); err != nil {
+			return models.WorkHistory{}, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return models.WorkHistory{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return updated, nil
 }
 
-// isValidField проверяет, является ли поле валидным для сортировки и фильтрации
-func (w *WorkHistoryRepo) isValidField(field string) bool {
-	validFields := map[string]bool{
-		"id":           true,
-		"name":         true,
-		"about":        true,
-		"period_start": true,
-		"period_end":   true,
+// Technologies получает технологии, использованные на месте работы, в порядке id
+func (w *WorkHistoryRepo) Technologies(whID int64) ([]models.Technology, error) {
+	return w.technologies.Related(workHistoryTechnologies, whID)
+}
+
+// SetTechnologies заменяет технологии места работы на technologyIDs и возвращает новый список
+func (w *WorkHistoryRepo) SetTechnologies(whID int64, technologyIDs []int64) ([]models.Technology, error) {
+	if err := workHistoryTechnologies.Set(w.db, whID, technologyIDs); err != nil {
+		return nil, err
+	}
+	return w.Technologies(whID)
+}
+
+// TechnologiesByWorkHistory получает технологии сразу нескольких мест работы одним запросом
+func (w *WorkHistoryRepo) TechnologiesByWorkHistory(whIDs []int64) (map[int64][]models.Technology, error) {
+	return w.technologies.RelatedByOwner(workHistoryTechnologies, whIDs)
+}
+
+// TagsByTechnology получает теги технологий одним запросом, для include=technologies.tags
+func (w *WorkHistoryRepo) TagsByTechnology(techIDs []int64) (map[int64][]models.Tag, error) {
+	return w.tags.RelatedByOwner(technologyTags, techIDs)
+}
+
+// workHistoryTechnologies связь истории работы с технологиями
+var workHistoryTechnologies = entityreqdecorator.Relation{
+	Table:        "work_history_technology",
+	OwnerTable:   "work_history",
+	OwnerColumn:  "work_history_id",
+	OwnerName:    "work history",
+	TargetTable:  "technology",
+	TargetColumn: "technology_id",
+	TargetName:   "technology",
+}
+
+// workHistorySchema колонки истории работы, доступные в запросах списка
+var workHistorySchema = entityreqdecorator.Schema{
+	{Name: "id", Type: entityreqdecorator.TypeInt, Filterable: true, Sortable: true},
+	{Name: "name", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true},
+	{Name: "about", Type: entityreqdecorator.TypeText, Filterable: true, Sortable: true},
+	{Name: "period_start", Alias: "periodStart", Type: entityreqdecorator.TypeDate, Filterable: true, Sortable: true},
+	{Name: "period_end", Alias: "periodEnd", Type: entityreqdecorator.TypeDate, Filterable: true, Sortable: true, Nullable: true},
+	{Name: "logo_url", Alias: "logoUrl", Type: entityreqdecorator.TypeJSON, Nullable: true},
+	{Name: "what_i_did", Alias: "whatIDid", Type: entityreqdecorator.TypeTextArray, Nullable: true},
+	{Name: "projects", Type: entityreqdecorator.TypeTextArray, Nullable: true},
+	// tech=go: места работы, где использовалась технология Go
+	{Name: "tech", Type: entityreqdecorator.TypeText, Filterable: true, IgnoreCase: true, Operators: relationOperators,
+		Relation: &entityreqdecorator.RelationFilter{Relation: workHistoryTechnologies, Column: "title"}},
+}
+
+// workHistoryColumns сопоставляет колонки с полями модели для сканирования выбранных колонок
+func workHistoryColumns(w *models.WorkHistory) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           &w.ID,
+		"name":         &w.Name,
+		"about":        &w.About,
+		"logo_url":     &w.LogoUrl,
+		"period_start": &w.PeriodStart,
+		"period_end":   &w.PeriodEnd,
+		"what_i_did":   pq.Array(&w.WhatIDid),
+		"projects":     pq.Array(&w.Projects),
 	}
-	return validFields[field]
 }
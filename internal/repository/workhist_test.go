@@ -5,6 +5,7 @@ import (
 	"time"
 
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
@@ -42,7 +43,7 @@ func TestWorkHistoryRepo_Create(t *testing.T) {
 			workHistory: models.WorkHistory{
 				Name:        "Company A",
 				About:       "IT компания",
-				LogoUrl:     []byte(`{"url": "https://example.com/logo.png"}`),
+				LogoUrl:     &types.LogoVariants{Original: "https://example.com/logo.png", Sm: "https://example.com/logo_32.png"},
 				PeriodStart: newPgDate(2020, time.January, 1),
 				PeriodEnd:   newPgDate(2023, time.December, 31),
 				WhatIDid:    []string{"Backend development", "Code review"},
@@ -78,7 +79,7 @@ func TestWorkHistoryRepo_Create(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			created, err := repo.Create(tt.workHistory)
+			created, err := repo.Create(tt.workHistory, nil)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -89,6 +90,7 @@ func TestWorkHistoryRepo_Create(t *testing.T) {
 			assert.NotZero(t, created.ID)
 			assert.Equal(t, tt.workHistory.Name, created.Name)
 			assert.Equal(t, tt.workHistory.About, created.About)
+			assert.Equal(t, tt.workHistory.LogoUrl, created.LogoUrl)
 			assertDatesEqual(t, tt.workHistory.PeriodStart, created.PeriodStart)
 			assertDatesEqual(t, tt.workHistory.PeriodEnd, created.PeriodEnd)
 			assert.Equal(t, tt.workHistory.WhatIDid, created.WhatIDid)
@@ -109,7 +111,7 @@ func TestWorkHistoryRepo_Get(t *testing.T) {
 		PeriodEnd:   newPgDate(2023, time.December, 31),
 		WhatIDid:    []string{"Task 1", "Task 2"},
 		Projects:    []string{"Project 1"},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -163,7 +165,7 @@ func TestWorkHistoryRepo_Update(t *testing.T) {
 		PeriodEnd:   newPgDate(2022, time.December, 31),
 		WhatIDid:    []string{"Original Task"},
 		Projects:    []string{"Original Project"},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -177,7 +179,7 @@ func TestWorkHistoryRepo_Update(t *testing.T) {
 				ID:          created.ID,
 				Name:        "Updated Company",
 				About:       "Updated About",
-				LogoUrl:     []byte(`{"url": "https://updated.com/logo.png"}`),
+				LogoUrl:     &types.LogoVariants{Original: "https://updated.com/logo.png"},
 				PeriodStart: newPgDate(2021, time.February, 1),
 				PeriodEnd:   newPgDate(2024, time.January, 15),
 				WhatIDid:    []string{"Updated Task 1", "Updated Task 2"},
@@ -199,7 +201,7 @@ func TestWorkHistoryRepo_Update(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			updated, err := repo.Update(tt.workHistory)
+			updated, err := repo.Update(tt.workHistory, nil)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -211,6 +213,7 @@ func TestWorkHistoryRepo_Update(t *testing.T) {
 			assert.Equal(t, tt.workHistory.ID, updated.ID)
 			assert.Equal(t, tt.workHistory.Name, updated.Name)
 			assert.Equal(t, tt.workHistory.About, updated.About)
+			assert.Equal(t, tt.workHistory.LogoUrl, updated.LogoUrl)
 			assertDatesEqual(t, tt.workHistory.PeriodStart, updated.PeriodStart)
 			assertDatesEqual(t, tt.workHistory.PeriodEnd, updated.PeriodEnd)
 			assert.Equal(t, tt.workHistory.WhatIDid, updated.WhatIDid)
@@ -230,7 +233,7 @@ func TestWorkHistoryRepo_Delete(t *testing.T) {
 		PeriodStart: newPgDate(2020, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -281,7 +284,7 @@ func TestWorkHistoryRepo_DeleteList(t *testing.T) {
 		PeriodStart: newPgDate(2020, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	wh2, err := repo.Create(models.WorkHistory{
@@ -290,7 +293,7 @@ func TestWorkHistoryRepo_DeleteList(t *testing.T) {
 		PeriodStart: newPgDate(2021, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	wh3, err := repo.Create(models.WorkHistory{
@@ -299,7 +302,7 @@ func TestWorkHistoryRepo_DeleteList(t *testing.T) {
 		PeriodStart: newPgDate(2022, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -382,7 +385,7 @@ func TestWorkHistoryRepo_List(t *testing.T) {
 	}
 
 	for _, wh := range workHistories {
-		_, err := repo.Create(wh)
+		_, err := repo.Create(wh, nil)
 		require.NoError(t, err)
 	}
 
@@ -453,7 +456,7 @@ func TestWorkHistoryRepo_List_WithFilter(t *testing.T) {
 		PeriodStart: newPgDate(2020, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	_, err = repo.Create(models.WorkHistory{
@@ -462,7 +465,7 @@ func TestWorkHistoryRepo_List_WithFilter(t *testing.T) {
 		PeriodStart: newPgDate(2022, time.June, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	// Фильтрация по имени
@@ -494,7 +497,7 @@ func TestWorkHistoryRepo_List_Sorting(t *testing.T) {
 		PeriodStart: newPgDate(2022, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	_, err = repo.Create(models.WorkHistory{
@@ -503,7 +506,7 @@ func TestWorkHistoryRepo_List_Sorting(t *testing.T) {
 		PeriodStart: newPgDate(2020, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	_, err = repo.Create(models.WorkHistory{
@@ -512,7 +515,7 @@ func TestWorkHistoryRepo_List_Sorting(t *testing.T) {
 		PeriodStart: newPgDate(2021, time.January, 1),
 		WhatIDid:    []string{},
 		Projects:    []string{},
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	// Сортировка по имени ASC
@@ -565,7 +568,7 @@ func TestWorkHistoryRepo_ArrayFields(t *testing.T) {
 		},
 	}
 
-	created, err := repo.Create(wh)
+	created, err := repo.Create(wh, nil)
 	require.NoError(t, err)
 	assert.Len(t, created.WhatIDid, 4)
 	assert.Len(t, created.Projects, 3)
@@ -580,10 +583,167 @@ func TestWorkHistoryRepo_ArrayFields(t *testing.T) {
 	created.WhatIDid = []string{"New task 1", "New task 2"}
 	created.Projects = []string{"New project"}
 
-	updated, err := repo.Update(created)
+	updated, err := repo.Update(created, nil)
 	require.NoError(t, err)
 	assert.Len(t, updated.WhatIDid, 2)
 	assert.Len(t, updated.Projects, 1)
 	assert.Equal(t, "New task 1", updated.WhatIDid[0])
 	assert.Equal(t, "New project", updated.Projects[0])
 }
+
+func TestWorkHistoryRepo_Technologies(t *testing.T) {
+	cleanupAllTables(t)
+	repo := NewWorkHistoryRepo(testDB)
+	techRepo := NewTechnologyRepo(testDB)
+
+	var techIDs []int64
+	for _, title := range []string{"Go", "PostgreSQL", "Kafka"} {
+		tech, err := techRepo.Create(models.Technology{Title: title})
+		require.NoError(t, err)
+		techIDs = append(techIDs, tech.ID)
+	}
+
+	wh := models.WorkHistory{
+		Name:        "Company",
+		About:       "Backend",
+		PeriodStart: newPgDate(2020, time.January, 1),
+	}
+
+	t.Run("создание с технологиями", func(t *testing.T) {
+		created, err := repo.Create(wh, []int64{techIDs[1], techIDs[0]})
+		require.NoError(t, err)
+
+		technologies, err := repo.Technologies(created.ID)
+		require.NoError(t, err)
+		require.Len(t, technologies, 2)
+		assert.Equal(t, techIDs[0], technologies[0].ID)
+		assert.Equal(t, techIDs[1], technologies[1].ID)
+	})
+
+	t.Run("создание с несуществующей технологией откатывается", func(t *testing.T) {
+		_, err := repo.Create(models.WorkHistory{
+			Name:        "Rollback",
+			About:       "Backend",
+			PeriodStart: newPgDate(2021, time.January, 1),
+		}, []int64{techIDs[0], 99999})
+		var missing *entityreqdecorator.MissingError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, []int64{99999}, missing.IDs)
+
+		var count int
+		require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM work_history WHERE name = 'Rollback'").Scan(&count))
+		assert.Zero(t, count)
+	})
+
+	t.Run("обновление без technologyIds не меняет технологии", func(t *testing.T) {
+		created, err := repo.Create(wh, []int64{techIDs[0]})
+		require.NoError(t, err)
+
+		created.About = "Updated"
+		_, err = repo.Update(created, nil)
+		require.NoError(t, err)
+		technologies, err := repo.Technologies(created.ID)
+		require.NoError(t, err)
+		assert.Len(t, technologies, 1)
+
+		_, err = repo.Update(created, []int64{techIDs[2]})
+		require.NoError(t, err)
+		technologies, err = repo.Technologies(created.ID)
+		require.NoError(t, err)
+		require.Len(t, technologies, 1)
+		assert.Equal(t, techIDs[2], technologies[0].ID)
+	})
+
+	t.Run("замена технологий", func(t *testing.T) {
+		created, err := repo.Create(wh, nil)
+		require.NoError(t, err)
+
+		technologies, err := repo.SetTechnologies(created.ID, techIDs)
+		require.NoError(t, err)
+		assert.Len(t, technologies, 3)
+
+		_, err = repo.SetTechnologies(99999, techIDs)
+		require.ErrorIs(t, err, entityreqdecorator.ErrNotFound)
+	})
+}
+
+func TestWorkHistoryRepo_TechnologiesByWorkHistory(t *testing.T) {
+	cleanupAllTables(t)
+	repo := NewWorkHistoryRepo(testDB)
+	techRepo := NewTechnologyRepo(testDB)
+	tagRepo := NewTagRepo(testDB)
+
+	goTech, err := techRepo.Create(models.Technology{Title: "Go"})
+	require.NoError(t, err)
+	pgTech, err := techRepo.Create(models.Technology{Title: "PostgreSQL"})
+	require.NoError(t, err)
+	tag, err := tagRepo.Create(models.Tag{Name: "Backend", HexColor: "#ffffff"})
+	require.NoError(t, err)
+	_, err = techRepo.SetTags(goTech.ID, []int64{tag.ID})
+	require.NoError(t, err)
+
+	wh := models.WorkHistory{Name: "Company", About: "Backend", PeriodStart: newPgDate(2020, time.January, 1)}
+	wh1, err := repo.Create(wh, []int64{pgTech.ID, goTech.ID})
+	require.NoError(t, err)
+	wh2, err := repo.Create(wh, []int64{goTech.ID})
+	require.NoError(t, err)
+	wh3, err := repo.Create(wh, nil)
+	require.NoError(t, err)
+
+	technologies, err := repo.TechnologiesByWorkHistory([]int64{wh1.ID, wh2.ID, wh3.ID})
+	require.NoError(t, err)
+	require.Len(t, technologies[wh1.ID], 2)
+	assert.Equal(t, goTech.ID, technologies[wh1.ID][0].ID)
+	assert.Equal(t, pgTech.ID, technologies[wh1.ID][1].ID)
+	require.Len(t, technologies[wh2.ID], 1)
+	assert.NotContains(t, technologies, wh3.ID)
+
+	tags, err := repo.TagsByTechnology([]int64{goTech.ID, pgTech.ID})
+	require.NoError(t, err)
+	require.Len(t, tags[goTech.ID], 1)
+	assert.Equal(t, "Backend", tags[goTech.ID][0].Name)
+	assert.NotContains(t, tags, pgTech.ID)
+
+	empty, err := repo.TechnologiesByWorkHistory(nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestWorkHistoryRepo_List_FilterByTechnology(t *testing.T) {
+	cleanupAllTables(t)
+	repo := NewWorkHistoryRepo(testDB)
+	techRepo := NewTechnologyRepo(testDB)
+
+	goTech, err := techRepo.Create(models.Technology{Title: "Go"})
+	require.NoError(t, err)
+	phpTech, err := techRepo.Create(models.Technology{Title: "PHP"})
+	require.NoError(t, err)
+
+	first, err := repo.Create(models.WorkHistory{Name: "First", About: "Backend", PeriodStart: newPgDate(2015, time.January, 1)}, []int64{phpTech.ID})
+	require.NoError(t, err)
+	second, err := repo.Create(models.WorkHistory{Name: "Second", About: "Backend", PeriodStart: newPgDate(2019, time.January, 1)}, []int64{goTech.ID, phpTech.ID})
+	require.NoError(t, err)
+	third, err := repo.Create(models.WorkHistory{Name: "Third", About: "Backend", PeriodStart: newPgDate(2022, time.January, 1)}, []int64{goTech.ID})
+	require.NoError(t, err)
+
+	req, err := entityreqdecorator.ParseQueryParamsStrict(map[string][]string{
+		"tech": {"go"},
+		"sort": {"periodStart,ASC"},
+	})
+	require.NoError(t, err)
+	result, err := repo.List(req)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, second.ID, result.Content[0].ID)
+	assert.Equal(t, third.ID, result.Content[1].ID)
+
+	req, err = entityreqdecorator.ParseQueryParamsStrict(map[string][]string{
+		"tech":        {"php"},
+		"periodStart": {"lt(2018-01-01)"},
+	})
+	require.NoError(t, err)
+	result, err = repo.List(req)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, first.ID, result.Content[0].ID)
+}
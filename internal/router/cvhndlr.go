@@ -0,0 +1,68 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Maxim-Ba/cv-backend/internal/services"
+)
+
+// CVHandler хендлер публичного резюме
+type CVHandler struct {
+	service *services.CVService
+}
+
+// NewCVHandler создает новый экземпляр хендлера резюме
+func NewCVHandler(cs *services.CVService) *CVHandler {
+	return &CVHandler{
+		service: cs,
+	}
+}
+
+// CV отдает резюме одним документом: GET /api/cv.
+// ETag считается по содержимому ответа, при совпадении If-None-Match отдается 304.
+func (ch *CVHandler) CV(w http.ResponseWriter, r *http.Request) {
+	cv, err := ch.service.Get()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	body, err := json.Marshal(cv)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	// Клиент должен перепроверять резюме при каждом запросе, изменения видны сразу
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// etagMatches проверяет If-None-Match: список тегов через запятую или "*".
+// Слабые теги (W/"...") сравниваются по значению.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -40,16 +40,20 @@ func (eh *EducationHandler) EducationGet(w http.ResponseWriter, r *http.Request)
 
 	education, err := eh.service.Get(eduID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeRelationError(w, err)
 		return
 	}
 
+	var body interface{} = education
+	if fields := entityreqdecorator.ParseFields(r.URL.Query()); fields != nil {
+		if body, err = entityreqdecorator.Project(education, fields, eh.service.Schema()); err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(education); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -57,15 +61,34 @@ func (eh *EducationHandler) EducationGet(w http.ResponseWriter, r *http.Request)
 // EducationList получает список записей образования
 func (eh *EducationHandler) EducationList(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
-	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
+	pagebleRq, err := entityreqdecorator.ParseQueryParamsStrict(queryParams)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
 	list, err := eh.service.List(pagebleRq)
 
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeQueryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// EducationSearch получает список записей образования по фильтру из тела запроса
+func (eh *EducationHandler) EducationSearch(w http.ResponseWriter, r *http.Request) {
+	pagebleRq, err := entityreqdecorator.DecodeSearch(r.Body)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	list, err := eh.service.List(pagebleRq)
+	if err != nil {
+		writeQueryError(w, err)
 		return
 	}
 
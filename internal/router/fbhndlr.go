@@ -1,8 +1,227 @@
 package router
 
-import "net/http"
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
 
-func FeedBackGet(w http.ResponseWriter, r *http.Request)  {}
-func FeedBackList(w http.ResponseWriter, r *http.Request) {}
+	"github.com/go-chi/chi/v5"
 
-func FeedBackCreate(w http.ResponseWriter, r *http.Request) {}
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/Maxim-Ba/cv-backend/internal/services"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+)
+
+// FeedbackHandler хендлер для работы с обратной связью
+type FeedbackHandler struct {
+	service    *services.FeedbackService
+	formTokens *services.FormTokens
+}
+
+// NewFeedbackHandler создает новый экземпляр хендлера обратной связи
+func NewFeedbackHandler(fs *services.FeedbackService, formTokens *services.FormTokens) *FeedbackHandler {
+	return &FeedbackHandler{
+		service:    fs,
+		formTokens: formTokens,
+	}
+}
+
+// FeedBackToken выдает токен формы обратной связи; фронтенд запрашивает его при открытии формы
+func (fh *FeedbackHandler) FeedBackToken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]string{
+		"formToken": fh.formTokens.Issue(),
+	})
+}
+
+// FeedBackGet получает одно сообщение по ID
+func (fh *FeedbackHandler) FeedBackGet(w http.ResponseWriter, r *http.Request) {
+	fbIDStr := chi.URLParam(r, "fbID")
+	fbID, err := strconv.ParseInt(fbIDStr, 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid feedback ID",
+		})
+		return
+	}
+
+	feedback, err := fh.service.Get(fbID)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	var body interface{} = feedback
+	if fields := entityreqdecorator.ParseFields(r.URL.Query()); fields != nil {
+		if body, err = entityreqdecorator.Project(feedback, fields, fh.service.Schema()); err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// FeedBackList получает список сообщений
+func (fh *FeedbackHandler) FeedBackList(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	pagebleRq, err := entityreqdecorator.ParseQueryParamsStrict(queryParams)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	list, err := fh.service.List(pagebleRq)
+
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// FeedBackSearch получает список сообщений по фильтру из тела запроса
+func (fh *FeedbackHandler) FeedBackSearch(w http.ResponseWriter, r *http.Request) {
+	pagebleRq, err := entityreqdecorator.DecodeSearch(r.Body)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	list, err := fh.service.List(pagebleRq)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// FeedBackCreate сохраняет сообщение посетителя сайта.
+// Поле website — honeypot, скрытое от людей; formToken выдается FeedBackToken.
+func (fh *FeedbackHandler) FeedBackCreate(w http.ResponseWriter, r *http.Request) {
+	var reqData struct {
+		AuthorName string `json:"authorName"`
+		Contact    string `json:"contact"`
+		Message    string `json:"message"`
+		Website    string `json:"website"`
+		FormToken  string `json:"formToken"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	submission := services.FeedbackSubmission{
+		Feedback: models.Feedback{
+			AuthorName: reqData.AuthorName,
+			Contact:    reqData.Contact,
+			Message:    reqData.Message,
+			SourceIp:   clientIP(r),
+			UserAgent:  r.UserAgent(),
+		},
+		Honeypot:  reqData.Website,
+		FormToken: reqData.FormToken,
+	}
+
+	created, err := fh.service.Submit(submission)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidFeedback) {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Посетителю не показываем статус: бот не должен узнать, что попал в спам
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":        created.ID,
+		"createdAt": created.CreatedAt,
+	}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// FeedBackStatus меняет статус одного или нескольких сообщений
+func (fh *FeedbackHandler) FeedBackStatus(w http.ResponseWriter, r *http.Request) {
+	var statusReq struct {
+		IDs    []int64 `json:"ids"`
+		Status string  `json:"status"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&statusReq); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	var updatedIDs []int64
+	var err error
+
+	if len(statusReq.IDs) == 1 {
+		updated, changeErr := fh.service.ChangeStatus(statusReq.IDs[0], statusReq.Status)
+		if changeErr != nil {
+			err = changeErr
+		} else {
+			updatedIDs = []int64{updated.ID}
+		}
+	} else {
+		updatedIDs, err = fh.service.BulkChangeStatus(statusReq.IDs, statusReq.Status)
+	}
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidStatusTransition) {
+			status = http.StatusUnprocessableEntity
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"updated_ids": updatedIDs,
+		"count":       len(updatedIDs),
+	})
+}
+
+// clientIP возвращает IP клиента; RemoteAddr уже подменен middleware.RealIP, если есть прокси-заголовки
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
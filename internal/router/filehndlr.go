@@ -0,0 +1,76 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Maxim-Ba/cv-backend/internal/services"
+)
+
+// FileHandler хендлер для загрузки файлов
+type FileHandler struct {
+	service *services.FileService
+}
+
+// NewFileHandler создает новый экземпляр хендлера файлов
+func NewFileHandler(fs *services.FileService) *FileHandler {
+	return &FileHandler{
+		service: fs,
+	}
+}
+
+// FileUpload принимает multipart/form-data с изображением в поле "file"
+// и возвращает постоянные URL оригинала и уменьшенных копий
+func (fh *FileHandler) FileUpload(w http.ResponseWriter, r *http.Request) {
+	// Запас на заголовки multipart поверх максимального размера файла
+	r.Body = http.MaxBytesReader(w, r.Body, fh.service.MaxSize()+1<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		status := http.StatusBadRequest
+		message := "File is required in the \"file\" form field"
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+			message = services.ErrFileTooLarge.Error()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": message,
+		})
+		return
+	}
+	defer file.Close()
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	uploaded, err := fh.service.Upload(file)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrEmptyFile), errors.Is(err, services.ErrInvalidImage):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrUnsupportedFileType):
+			status = http.StatusUnsupportedMediaType
+		case errors.Is(err, services.ErrFileTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		default:
+			slog.Error(err.Error())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(uploaded); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
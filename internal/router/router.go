@@ -3,15 +3,22 @@ package router
 import (
 	//...
 
+	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/gorilla/csrf"
 
+	"github.com/Maxim-Ba/cv-backend/config"
 	m "github.com/Maxim-Ba/cv-backend/internal/middleware"
 	"github.com/Maxim-Ba/cv-backend/internal/services"
+	"github.com/Maxim-Ba/cv-backend/internal/view/components/components"
 	"github.com/Maxim-Ba/cv-backend/internal/view/components/pages"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 )
@@ -19,6 +26,7 @@ import (
 type Router struct {
 	R    *chi.Mux
 	Deps *Dependencies
+	cfg  *config.Config
 }
 
 type Dependencies struct {
@@ -26,14 +34,21 @@ type Dependencies struct {
 	TechService        *services.TechService
 	EducationService   *services.EducationService
 	WorkHistoryService *services.WorkHistoryService
+	AuthService        *services.AuthService
+	ApiTokenService    *services.ApiTokenService
+	FeedbackService    *services.FeedbackService
+	FormTokens         *services.FormTokens
+	FileService        *services.FileService
+	SearchService      *services.SearchService
+	CVService          *services.CVService
 }
 
-func New(deps *Dependencies) *Router {
+func New(deps *Dependencies, cfg *config.Config) *Router {
 	r := chi.NewRouter()
 
 csrfMiddleware := csrf.Protect(
-		[]byte("32-byte-long-auth-key"), 
-		csrf.Secure(false),              
+		cfg.CSRFKey,
+		csrf.Secure(cfg.SecureCookie),
 		csrf.FieldName("csrf_token"),
 		csrf.CookieName("csrf_token"),
 	)
@@ -42,66 +57,109 @@ csrfMiddleware := csrf.Protect(
 	r.Use(middleware.RequestLogger(logger))
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
+	r.Use(m.TokenAuth(deps.ApiTokenService))
+	// POST /search только читает данные, поэтому, как и GET-списки, не требует CSRF-токена
+	r.Use(m.SkipCSRF(
+		"POST /api/fb",
+		"POST /api/tag/search",
+		"POST /api/tech/search",
+		"POST /api/wh/search",
+		"POST /api/edu/search",
+		"POST /api/fb/search",
+	))
 	r.Use(csrfMiddleware)
+	r.Use(m.SessionAuth(deps.AuthService))
 
 	router := &Router{
 		R:    r,
 		Deps: deps,
+		cfg:  cfg,
 	}
 
 	h := createHandlers(deps)
 
 	fs := http.FileServer(http.Dir("internal/view/static"))
 	r.Handle("/static/*", http.StripPrefix("/static/", fs))
+	if cfg.StorageBackend == "local" {
+		// Загруженные файлы раздаются приложением только при локальном хранилище
+		files := http.FileServer(http.Dir(cfg.StorageDir))
+		r.Handle("/files/*", http.StripPrefix("/files/", files))
+	}
 
 	r.Route("/admin", func(r chi.Router) {
-		r.Get("/", router.adminDashboard)
-		r.Get("/tag", router.adminTags)
-		r.Get("/tech", router.adminTech)
-		r.Get("/history", router.admiHistory)
-		r.Get("/education", router.adminEducation)
 		r.Get("/login", router.adminLogin)
 		r.Post("/login", router.adminLoginPost)
+
+		r.Group(func(r chi.Router) {
+			r.Use(m.RequireAdmin)
+			r.With(m.RequirePermission(services.PermAdminRead)).Get("/", router.adminDashboard)
+			r.With(m.RequirePermission(services.PermAdminRead)).Get("/tag", router.adminTags)
+			r.With(m.RequirePermission(services.PermAdminRead)).Get("/tech", router.adminTech)
+			r.With(m.RequirePermission(services.PermAdminRead)).Get("/history", router.admiHistory)
+			r.With(m.RequirePermission(services.PermAdminRead)).Get("/education", router.adminEducation)
+			r.With(m.RequirePermission(services.PermFbRead)).Get("/feedback", router.adminFeedback)
+			r.With(m.RequirePermission(services.PermFbWrite)).Post("/feedback/status", router.adminFeedbackStatus)
+			r.With(m.RequirePermission(services.PermUserManage)).Get("/tokens", router.adminTokens)
+			r.With(m.RequirePermission(services.PermUserManage)).Post("/tokens", router.adminTokenIssue)
+			r.With(m.RequirePermission(services.PermUserManage)).Post("/tokens/{tokenID}/revoke", router.adminTokenRevoke)
+			r.Post("/logout", router.adminLogout)
+		})
 	})
 
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/tag", func(r chi.Router) {
 			r.Get("/{tagID}", h.TagHandler.TagGet)
+			r.Get("/{tagID}/tech", h.TagHandler.TagTechnologies)
 			r.Get("/", h.TagHandler.TagList)
-			r.Post("/", h.TagHandler.TagCreate)
-			r.Delete("/", h.TagHandler.TagDelete)
-			r.Put("/", h.TagHandler.TagUpdate)
+			r.Post("/search", h.TagHandler.TagSearch)
+			r.With(m.RequirePermission(services.PermTagWrite)).Post("/", h.TagHandler.TagCreate)
+			r.With(m.RequirePermission(services.PermTagDelete)).Delete("/", h.TagHandler.TagDelete)
+			r.With(m.RequirePermission(services.PermTagWrite)).Put("/", h.TagHandler.TagUpdate)
 		})
 		//
 		r.Route("/tech", func(r chi.Router) {
 			r.Get("/{techID}", h.TechHandler.TechGet)
+			r.Get("/{techID}/tags", h.TechHandler.TechTags)
+			r.With(m.RequirePermission(services.PermTechWrite)).Put("/{techID}/tags", h.TechHandler.TechSetTags)
 			r.Get("/", h.TechHandler.TechList)
-			r.Post("/", h.TechHandler.TechCreate)
-			r.Delete("/", h.TechHandler.TechDelete)
-			r.Put("/", h.TechHandler.TechUpdate)
+			r.Post("/search", h.TechHandler.TechSearch)
+			r.With(m.RequirePermission(services.PermTechWrite)).Post("/", h.TechHandler.TechCreate)
+			r.With(m.RequirePermission(services.PermTechDelete)).Delete("/", h.TechHandler.TechDelete)
+			r.With(m.RequirePermission(services.PermTechWrite)).Put("/", h.TechHandler.TechUpdate)
 		})
 		//
 		r.Route("/wh", func(r chi.Router) {
 			r.Get("/{whID}", h.WorkHistoryHandler.WorkHistoryGet)
+			r.Get("/{whID}/tech", h.WorkHistoryHandler.WorkHistoryTechnologies)
+			r.With(m.RequirePermission(services.PermWHWrite)).Put("/{whID}/tech", h.WorkHistoryHandler.WorkHistorySetTechnologies)
 			r.Get("/", h.WorkHistoryHandler.WorkHistoryList)
-			r.Post("/", h.WorkHistoryHandler.WorkHistoryCreate)
-			r.Delete("/", h.WorkHistoryHandler.WorkHistoryDelete)
-			r.Put("/", h.WorkHistoryHandler.WorkHistoryUpdate)
+			r.Post("/search", h.WorkHistoryHandler.WorkHistorySearch)
+			r.With(m.RequirePermission(services.PermWHWrite)).Post("/", h.WorkHistoryHandler.WorkHistoryCreate)
+			r.With(m.RequirePermission(services.PermWHDelete)).Delete("/", h.WorkHistoryHandler.WorkHistoryDelete)
+			r.With(m.RequirePermission(services.PermWHWrite)).Put("/", h.WorkHistoryHandler.WorkHistoryUpdate)
 		})
 		//
 		r.Route("/edu", func(r chi.Router) {
 			r.Get("/{eduID}", h.EducationHandler.EducationGet)
 			r.Get("/", h.EducationHandler.EducationList)
-			r.Post("/", h.EducationHandler.EducationCreate)
-			r.Delete("/", h.EducationHandler.EducationDelete)
-			r.Put("/", h.EducationHandler.EducationUpdate)
+			r.Post("/search", h.EducationHandler.EducationSearch)
+			r.With(m.RequirePermission(services.PermEduWrite)).Post("/", h.EducationHandler.EducationCreate)
+			r.With(m.RequirePermission(services.PermEduDelete)).Delete("/", h.EducationHandler.EducationDelete)
+			r.With(m.RequirePermission(services.PermEduWrite)).Put("/", h.EducationHandler.EducationUpdate)
 		})
 		//
 		r.Route("/fb", func(r chi.Router) {
-			r.Get("/{fbID}", FeedBackGet)
-			r.Get("/", FeedBackList)
-			r.Post("/", FeedBackCreate)
+			r.With(m.RequirePermission(services.PermFbRead)).Get("/{fbID}", h.FeedbackHandler.FeedBackGet)
+			r.With(m.RequirePermission(services.PermFbRead)).Get("/", h.FeedbackHandler.FeedBackList)
+			r.With(m.RequirePermission(services.PermFbRead)).Post("/search", h.FeedbackHandler.FeedBackSearch)
+			r.Get("/token", h.FeedbackHandler.FeedBackToken)
+			r.With(m.RateLimit(cfg.FeedbackRateLimit, cfg.FeedbackRateWindow)).Post("/", h.FeedbackHandler.FeedBackCreate)
+			r.With(m.RequirePermission(services.PermFbWrite)).Put("/status", h.FeedbackHandler.FeedBackStatus)
 		})
+		//
+		r.With(m.RequirePermission(services.PermFileWrite)).Post("/files", h.FileHandler.FileUpload)
+		r.Get("/search", h.SearchHandler.Search)
+		r.Get("/cv", h.CVHandler.CV)
 
 	})
 
@@ -113,6 +171,10 @@ type handlers struct {
 	TechHandler        *TechHandler
 	EducationHandler   *EducationHandler
 	WorkHistoryHandler *WorkHistoryHandler
+	FeedbackHandler    *FeedbackHandler
+	FileHandler        *FileHandler
+	SearchHandler      *SearchHandler
+	CVHandler          *CVHandler
 }
 
 func createHandlers(deps *Dependencies) *handlers {
@@ -120,60 +182,102 @@ func createHandlers(deps *Dependencies) *handlers {
 	techHandler := NewTechHandler(deps.TechService)
 	educationHandler := NewEducationHandler(deps.EducationService)
 	workHistoryHandler := NewWorkHistoryHandler(deps.WorkHistoryService)
+	feedbackHandler := NewFeedbackHandler(deps.FeedbackService, deps.FormTokens)
+	fileHandler := NewFileHandler(deps.FileService)
+	searchHandler := NewSearchHandler(deps.SearchService)
+	cvHandler := NewCVHandler(deps.CVService)
 
 	return &handlers{
 		TagHandler:         tagHandler,
 		TechHandler:        techHandler,
 		EducationHandler:   educationHandler,
 		WorkHistoryHandler: workHistoryHandler,
+		FeedbackHandler:    feedbackHandler,
+		FileHandler:        fileHandler,
+		SearchHandler:      searchHandler,
+		CVHandler:          cvHandler,
 	}
 }
 
 func (rt *Router) adminDashboard(w http.ResponseWriter, r *http.Request) {
-	user := "Администратор"
-	component := pages.AdminPage(user)
+	component := pages.AdminPage(rt.adminSession(r))
 	component.Render(r.Context(), w)
 }
 func (rt *Router) adminEducation(w http.ResponseWriter, r *http.Request) {
-	user := "Администратор"
-	component := pages.EducationPage(user)
+	component := pages.EducationPage(rt.adminSession(r))
 	component.Render(r.Context(), w)
 }
 func (rt *Router) admiHistory(w http.ResponseWriter, r *http.Request) {
-	user := "Администратор"
-	component := pages.HistoryPage(user)
+	component := pages.HistoryPage(rt.adminSession(r))
 	component.Render(r.Context(), w)
 }
 
 func (rt *Router) adminTech(w http.ResponseWriter, r *http.Request) {
-	user := "Администратор"
 	queryParams := r.URL.Query()
 	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
 	techResult, err := rt.Deps.TechService.List(pagebleRq)
 	if err != nil {
 		slog.Error(err.Error())
 	}
-	csrfToken := csrf.Token(r)
-	
+
 	editID := r.URL.Query().Get("edit")
-	component := pages.TechPage(user, techResult, editID, csrfToken )
+	component := pages.TechPage(rt.adminSession(r), techResult, editID)
 	component.Render(r.Context(), w)
 }
 
 func (rt *Router) adminTags(w http.ResponseWriter, r *http.Request) {
-	user := "Администратор"
 	queryParams := r.URL.Query()
 	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
 	tagsResult, err := rt.Deps.TagService.List(pagebleRq)
 	if err != nil {
 		slog.Error(err.Error())
 	}
-	component := pages.TagPage(user, tagsResult)
+	component := pages.TagPage(rt.adminSession(r), tagsResult)
 	component.Render(r.Context(), w)
 }
 
+func (rt *Router) adminFeedback(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
+	if len(pagebleRq.Sort) == 0 {
+		pagebleRq.Sort = []entityreqdecorator.SortBy{{Field: "id", Order: "DESC"}}
+	}
+	feedbackResult, err := rt.Deps.FeedbackService.List(pagebleRq)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	component := pages.FeedbackPage(rt.adminSession(r), feedbackResult, queryParams.Get("status"))
+	component.Render(r.Context(), w)
+}
+
+func (rt *Router) adminFeedbackStatus(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	var ids []int64
+	for _, idStr := range r.Form["ids"] {
+		if id, err := strconv.ParseInt(idStr, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if _, err := rt.Deps.FeedbackService.BulkChangeStatus(ids, r.FormValue("status")); err != nil {
+		slog.Error(err.Error())
+	}
+
+	redirect := "/admin/feedback"
+	if filter := r.FormValue("filter"); filter != "" {
+		redirect += "?" + url.Values{"status": {filter}}.Encode()
+	}
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
 func (rt *Router) adminLogin(w http.ResponseWriter, r *http.Request) {
-	component := pages.Login("")
+	if _, ok := m.PrincipalFromContext(r.Context()); ok {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	component := pages.Login("", csrf.Token(r))
 	component.Render(r.Context(), w)
 }
 
@@ -185,10 +289,159 @@ func (rt *Router) adminLoginPost(w http.ResponseWriter, r *http.Request) {
 	username := r.FormValue("username")
 	password := r.FormValue("password")
 
-	if username != "admin" || password != "admin" {
-		component := pages.Login("Неверный логин или пароль")
+	token, session, err := rt.Deps.AuthService.Login(username, password)
+	if err != nil {
+		if !errors.Is(err, services.ErrInvalidCredentials) {
+			slog.Error(err.Error())
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		component := pages.Login("Неверный логин или пароль", csrf.Token(r))
 		component.Render(r.Context(), w)
 		return
 	}
+	rt.setSessionCookie(w, token, session.ExpiresAt)
 	http.Redirect(w, r, "/admin", http.StatusSeeOther)
 }
+
+func (rt *Router) adminLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(m.SessionCookieName); err == nil {
+		if err := rt.Deps.AuthService.Logout(cookie.Value); err != nil {
+			slog.Error(err.Error())
+		}
+	}
+	rt.setSessionCookie(w, "", time.Unix(0, 0))
+	http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+}
+
+func (rt *Router) adminTokens(w http.ResponseWriter, r *http.Request) {
+	rt.renderTokens(w, r, http.StatusOK, pages.TokensPageData{})
+}
+
+func (rt *Router) adminTokenIssue(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	principal, _ := m.PrincipalFromContext(r.Context())
+
+	ttlDays, err := strconv.Atoi(r.FormValue("ttl_days"))
+	if err != nil {
+		rt.renderTokens(w, r, http.StatusBadRequest, pages.TokensPageData{ErrorMessage: "Некорректный срок действия"})
+		return
+	}
+	ttl := time.Duration(ttlDays) * 24 * time.Hour
+
+	token, _, err := rt.Deps.ApiTokenService.Issue(principal.User.ID, r.FormValue("name"), r.Form["scopes"], ttl)
+	if err != nil {
+		rt.renderTokens(w, r, http.StatusBadRequest, pages.TokensPageData{ErrorMessage: err.Error()})
+		return
+	}
+	rt.renderTokens(w, r, http.StatusOK, pages.TokensPageData{NewToken: token})
+}
+
+func (rt *Router) adminTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	principal, _ := m.PrincipalFromContext(r.Context())
+	id, err := strconv.ParseInt(chi.URLParam(r, "tokenID"), 10, 64)
+	if err != nil {
+		rt.renderTokens(w, r, http.StatusBadRequest, pages.TokensPageData{ErrorMessage: "Некорректный ID токена"})
+		return
+	}
+	if err := rt.Deps.ApiTokenService.Revoke(principal.User.ID, id); err != nil {
+		slog.Error(err.Error())
+		rt.renderTokens(w, r, http.StatusNotFound, pages.TokensPageData{ErrorMessage: "Токен не найден"})
+		return
+	}
+	http.Redirect(w, r, "/admin/tokens", http.StatusSeeOther)
+}
+
+// renderTokens отрисовывает страницу токенов текущего пользователя
+func (rt *Router) renderTokens(w http.ResponseWriter, r *http.Request, status int, data pages.TokensPageData) {
+	principal, _ := m.PrincipalFromContext(r.Context())
+	tokens, err := rt.Deps.ApiTokenService.List(principal.User.ID)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	data.Tokens = tokens
+	w.WriteHeader(status)
+	component := pages.TokensPage(rt.adminSession(r), data)
+	component.Render(r.Context(), w)
+}
+
+// setSessionCookie устанавливает cookie сессии; пустой token удаляет cookie
+func (rt *Router) setSessionCookie(w http.ResponseWriter, token string, expires time.Time) {
+	cookie := &http.Cookie{
+		Name:     m.SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   rt.cfg.SecureCookie,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if token == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+// adminSession собирает данные текущей сессии для шаблонов админки
+func (rt *Router) adminSession(r *http.Request) components.AdminSession {
+	principal, _ := m.PrincipalFromContext(r.Context())
+	session := components.AdminSession{
+		User:      principal.User.Username,
+		Role:      principal.Role.Title,
+		CSRFToken: csrf.Token(r),
+	}
+	if principal.Can(services.PermFbRead) {
+		unread, err := rt.Deps.FeedbackService.CountUnread()
+		if err != nil {
+			slog.Error(err.Error())
+		}
+		session.UnreadFeedback = unread
+	}
+	return session
+}
+
+// writeRelationError отдает ошибку получения записи или работы со связями: запись не найдена — 404,
+// ссылки на несуществующие связанные записи — 422 со списком их ID, остальные — 500
+func writeRelationError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	var missing *entityreqdecorator.MissingError
+	switch {
+	case errors.Is(err, entityreqdecorator.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+	case errors.As(err, &missing):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error": missing.Error(),
+			"ids":   missing.IDs,
+		})
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+	}
+}
+
+// writeQueryError отдает ошибку запроса на чтение: ошибки в параметрах фильтрации,
+// сортировки и fields — 400 со списком параметров, остальные — 500
+func writeQueryError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	var qe *entityreqdecorator.QueryError
+	if errors.As(err, &qe) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error":  "invalid query parameters",
+			"params": qe.Params,
+		})
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Maxim-Ba/cv-backend/internal/services"
+)
+
+// SearchHandler хендлер полнотекстового поиска по резюме
+type SearchHandler struct {
+	service *services.SearchService
+}
+
+// NewSearchHandler создает новый экземпляр хендлера поиска
+func NewSearchHandler(ss *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: ss,
+	}
+}
+
+// Search ищет по технологиям, истории работы и образованию: GET /api/search?q=...&limit=20
+func (sh *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	var limit int
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error": "limit must be a positive integer",
+			})
+			return
+		}
+	}
+
+	hits, err := sh.service.Search(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidSearchQuery) {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"content": hits,
+	}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
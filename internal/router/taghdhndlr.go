@@ -37,32 +37,36 @@ func (th *TagHandler) TagGet(w http.ResponseWriter, r *http.Request) {
 
 	tag, err := th.service.Get(tagID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeRelationError(w, err)
 		return
 	}
 
+	var body interface{} = tag
+	if fields := entityreqdecorator.ParseFields(r.URL.Query()); fields != nil {
+		if body, err = entityreqdecorator.Project(tag, fields, th.service.Schema()); err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tag); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
 func (th *TagHandler) TagList(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
-	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
+	pagebleRq, err := entityreqdecorator.ParseQueryParamsStrict(queryParams)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
 	list, err := th.service.List(pagebleRq)
 
 	if err != nil {
-        w.Header().Set("Content-Type", "application/json")
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(map[string]string{
-            "error": err.Error(),
-        })
-        return
-    }
+		writeQueryError(w, err)
+		return
+	}
     
     w.Header().Set("Content-Type", "application/json")
     if err := json.NewEncoder(w).Encode(list); err != nil {
@@ -70,6 +74,25 @@ func (th *TagHandler) TagList(w http.ResponseWriter, r *http.Request) {
     }
 }
 
+// TagSearch получает список тегов по фильтру из тела запроса
+func (th *TagHandler) TagSearch(w http.ResponseWriter, r *http.Request) {
+	pagebleRq, err := entityreqdecorator.DecodeSearch(r.Body)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	list, err := th.service.List(pagebleRq)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 // TagCreate создает новый тег
 func (th *TagHandler) TagCreate(w http.ResponseWriter, r *http.Request) {
 	var reqData struct {
@@ -191,3 +214,27 @@ func (th *TagHandler) TagUpdate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
+
+// TagTechnologies получает технологии с тегом
+func (th *TagHandler) TagTechnologies(w http.ResponseWriter, r *http.Request) {
+	tagID, err := strconv.ParseInt(chi.URLParam(r, "tagID"), 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid tag ID",
+		})
+		return
+	}
+
+	technologies, err := th.service.Technologies(tagID)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(technologies); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
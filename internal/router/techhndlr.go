@@ -38,18 +38,38 @@ func (th *TechHandler) TechGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	include, err := entityreqdecorator.ParseInclude(r.URL.Query(), services.TechIncludes...)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
 	technology, err := th.service.Get(techID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeRelationError(w, err)
 		return
 	}
 
+	var item interface{} = technology
+	if include != nil {
+		expanded, err := th.service.Expand([]models.Technology{technology}, include)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		item = expanded[0]
+	}
+
+	body := item
+	if fields := entityreqdecorator.ParseFields(r.URL.Query()); fields != nil {
+		if body, err = entityreqdecorator.Project(item, fields, th.service.Schema()); err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(technology); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -57,15 +77,49 @@ func (th *TechHandler) TechGet(w http.ResponseWriter, r *http.Request) {
 // TechList получает список технологий
 func (th *TechHandler) TechList(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
-	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
+	pagebleRq, err := entityreqdecorator.ParseQueryParamsStrict(queryParams)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	include, err := entityreqdecorator.ParseInclude(queryParams, services.TechIncludes...)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
 	list, err := th.service.List(pagebleRq)
 
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeQueryError(w, err)
+		return
+	}
+
+	var body interface{} = list
+	if include != nil {
+		expanded, err := th.service.Expand(list.Content, include)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		body = entityreqdecorator.WithContent(list, expanded)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// TechSearch получает список технологий по фильтру из тела запроса
+func (th *TechHandler) TechSearch(w http.ResponseWriter, r *http.Request) {
+	pagebleRq, err := entityreqdecorator.DecodeSearch(r.Body)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	list, err := th.service.List(pagebleRq)
+	if err != nil {
+		writeQueryError(w, err)
 		return
 	}
 
@@ -200,3 +254,65 @@ func (th *TechHandler) TechUpdate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
+
+// TechTags получает теги технологии
+func (th *TechHandler) TechTags(w http.ResponseWriter, r *http.Request) {
+	techID, err := strconv.ParseInt(chi.URLParam(r, "techID"), 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid technology ID",
+		})
+		return
+	}
+
+	tags, err := th.service.Tags(techID)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// TechSetTags заменяет теги технологии: в теле передается полный список ID тегов,
+// лишние связи удаляются, недостающие добавляются
+func (th *TechHandler) TechSetTags(w http.ResponseWriter, r *http.Request) {
+	techID, err := strconv.ParseInt(chi.URLParam(r, "techID"), 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid technology ID",
+		})
+		return
+	}
+
+	var reqData struct {
+		TagIDs []int64 `json:"tagIds"`
+	}
+	// Без tagIds теги не очищаются: для этого нужно явно передать пустой список
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil || reqData.TagIDs == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	tags, err := th.service.SetTags(techID, reqData.TagIDs)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
 	"github.com/Maxim-Ba/cv-backend/internal/services"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 )
@@ -39,18 +40,36 @@ func (wh *WorkHistoryHandler) WorkHistoryGet(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	include, err := entityreqdecorator.ParseInclude(r.URL.Query(), services.WorkHistoryIncludes...)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
 	workHistory, err := wh.service.Get(whID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeRelationError(w, err)
 		return
 	}
 
+	var item interface{} = workHistory
+	if include != nil {
+		if item, err = wh.service.ExpandOne(workHistory, include); err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+
+	body := item
+	if fields := entityreqdecorator.ParseFields(r.URL.Query()); fields != nil {
+		if body, err = entityreqdecorator.Project(item, fields, wh.service.Schema()); err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(workHistory); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -58,15 +77,49 @@ func (wh *WorkHistoryHandler) WorkHistoryGet(w http.ResponseWriter, r *http.Requ
 // WorkHistoryList получает список записей истории работы
 func (wh *WorkHistoryHandler) WorkHistoryList(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
-	pagebleRq := entityreqdecorator.ParseQueryParams(queryParams)
+	pagebleRq, err := entityreqdecorator.ParseQueryParamsStrict(queryParams)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	include, err := entityreqdecorator.ParseInclude(queryParams, services.WorkHistoryIncludes...)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
 	list, err := wh.service.List(pagebleRq)
 
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeQueryError(w, err)
+		return
+	}
+
+	var body interface{} = list
+	if include != nil {
+		expanded, err := wh.service.Expand(list.Content, include)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		body = entityreqdecorator.WithContent(list, expanded)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// WorkHistorySearch получает список записей истории работы по фильтру из тела запроса
+func (wh *WorkHistoryHandler) WorkHistorySearch(w http.ResponseWriter, r *http.Request) {
+	pagebleRq, err := entityreqdecorator.DecodeSearch(r.Body)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	list, err := wh.service.List(pagebleRq)
+	if err != nil {
+		writeQueryError(w, err)
 		return
 	}
 
@@ -79,13 +132,14 @@ func (wh *WorkHistoryHandler) WorkHistoryList(w http.ResponseWriter, r *http.Req
 // WorkHistoryCreate создает новую запись истории работы
 func (wh *WorkHistoryHandler) WorkHistoryCreate(w http.ResponseWriter, r *http.Request) {
 	var reqData struct {
-		Name        string   `json:"name"`
-		About       string   `json:"about"`
-		LogoUrl     []byte   `json:"logoUrl"`
-		PeriodStart string   `json:"periodStart"`
-		PeriodEnd   string   `json:"periodEnd"`
-		WhatIDid    []string `json:"whatIDid"`
-		Projects    []string `json:"projects"`
+		Name          string              `json:"name"`
+		About         string              `json:"about"`
+		LogoUrl       *types.LogoVariants `json:"logoUrl"`
+		PeriodStart   string              `json:"periodStart"`
+		PeriodEnd     string              `json:"periodEnd"`
+		WhatIDid      []string            `json:"whatIDid"`
+		Projects      []string            `json:"projects"`
+		TechnologyIDs []int64             `json:"technologyIds"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
@@ -122,13 +176,9 @@ func (wh *WorkHistoryHandler) WorkHistoryCreate(w http.ResponseWriter, r *http.R
 		Projects:    reqData.Projects,
 	}
 
-	created, err := wh.service.Create(workHistory)
+	created, err := wh.service.Create(workHistory, reqData.TechnologyIDs)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
-		})
+		writeRelationError(w, err)
 		return
 	}
 
@@ -187,14 +237,15 @@ func (wh *WorkHistoryHandler) WorkHistoryDelete(w http.ResponseWriter, r *http.R
 // WorkHistoryUpdate обновляет запись истории работы
 func (wh *WorkHistoryHandler) WorkHistoryUpdate(w http.ResponseWriter, r *http.Request) {
 	var reqData struct {
-		ID          int64    `json:"id"`
-		Name        string   `json:"name"`
-		About       string   `json:"about"`
-		LogoUrl     []byte   `json:"logoUrl"`
-		PeriodStart string   `json:"periodStart"`
-		PeriodEnd   string   `json:"periodEnd"`
-		WhatIDid    []string `json:"whatIDid"`
-		Projects    []string `json:"projects"`
+		ID            int64               `json:"id"`
+		Name          string              `json:"name"`
+		About         string              `json:"about"`
+		LogoUrl       *types.LogoVariants `json:"logoUrl"`
+		PeriodStart   string              `json:"periodStart"`
+		PeriodEnd     string              `json:"periodEnd"`
+		WhatIDid      []string            `json:"whatIDid"`
+		Projects      []string            `json:"projects"`
+		TechnologyIDs []int64             `json:"technologyIds"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
@@ -232,18 +283,76 @@ func (wh *WorkHistoryHandler) WorkHistoryUpdate(w http.ResponseWriter, r *http.R
 		Projects:    reqData.Projects,
 	}
 
-	updated, err := wh.service.Update(workHistory)
+	// Без technologyIds технологии записи не меняются
+	updated, err := wh.service.Update(workHistory, reqData.TechnologyIDs)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// WorkHistoryTechnologies получает технологии места работы
+func (wh *WorkHistoryHandler) WorkHistoryTechnologies(w http.ResponseWriter, r *http.Request) {
+	whID, err := strconv.ParseInt(chi.URLParam(r, "whID"), 10, 64)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
-			"error": err.Error(),
+			"error": "Invalid work history ID",
 		})
 		return
 	}
 
+	technologies, err := wh.service.Technologies(whID)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(updated); err != nil {
+	if err := json.NewEncoder(w).Encode(technologies); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// WorkHistorySetTechnologies заменяет технологии места работы полным списком ID из тела запроса
+func (wh *WorkHistoryHandler) WorkHistorySetTechnologies(w http.ResponseWriter, r *http.Request) {
+	whID, err := strconv.ParseInt(chi.URLParam(r, "whID"), 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid work history ID",
+		})
+		return
+	}
+
+	var reqData struct {
+		TechnologyIDs []int64 `json:"technologyIds"`
+	}
+	// Без technologyIds технологии не очищаются: для этого нужно явно передать пустой список
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil || reqData.TechnologyIDs == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	technologies, err := wh.service.SetTechnologies(whID, reqData.TechnologyIDs)
+	if err != nil {
+		writeRelationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(technologies); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
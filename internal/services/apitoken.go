@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// ApiTokenPrefix префикс выпускаемых токенов, чтобы их было легко узнать в логах и конфигах
+const ApiTokenPrefix = "cvb_"
+
+// Действия в скоупах токенов. Скоуп имеет вид "<действие>:<ресурс>", например
+// "write:tech", и разрешает право "<ресурс>:<действие>", если оно есть у роли владельца токена.
+const (
+	ScopeRead   = "read"
+	ScopeWrite  = "write"
+	ScopeDelete = "delete"
+)
+
+// ScopeAny ресурс-шаблон, подходящий под любой ресурс: "read:*"
+const ScopeAny = "*"
+
+// ScopeActions список известных действий
+var ScopeActions = []string{ScopeRead, ScopeWrite, ScopeDelete}
+
+// ScopeResources список ресурсов, доступных через API-токены
+var ScopeResources = []string{"tag", "tech", "wh", "edu", "fb", "file"}
+
+// ApiTokenStore интерфейс хранилища API-токенов
+type ApiTokenStore interface {
+	GetByHash(hash string) (models.ApiToken, error)
+	ListByUser(userID int64) ([]models.ApiToken, error)
+	Create(models.ApiToken) (models.ApiToken, error)
+	Revoke(id, userID int64) error
+	TouchLastUsed(id int64) error
+}
+
+// ApiTokenService сервис персональных API-токенов
+type ApiTokenService struct {
+	tokens ApiTokenStore
+	auth   *AuthService
+	now    func() time.Time
+}
+
+// NewApiTokenService создает новый экземпляр сервиса API-токенов
+func NewApiTokenService(tokens ApiTokenStore, auth *AuthService) *ApiTokenService {
+	return &ApiTokenService{
+		tokens: tokens,
+		auth:   auth,
+		now:    time.Now,
+	}
+}
+
+// Issue выпускает токен пользователю. ttl = 0 — токен без срока действия.
+// Возвращает сам токен; в БД хранится только его хеш.
+func (s *ApiTokenService) Issue(userID int64, name string, scopes []string, ttl time.Duration) (string, models.ApiToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", models.ApiToken{}, fmt.Errorf("token name is required")
+	}
+	if len(scopes) == 0 {
+		return "", models.ApiToken{}, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if err := ValidateScope(scope); err != nil {
+			return "", models.ApiToken{}, err
+		}
+	}
+	if ttl < 0 {
+		return "", models.ApiToken{}, fmt.Errorf("token ttl must not be negative")
+	}
+
+	secret, err := newSessionToken()
+	if err != nil {
+		return "", models.ApiToken{}, fmt.Errorf("error generating api token: %w", err)
+	}
+	token := ApiTokenPrefix + secret
+
+	apiToken := models.ApiToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashSessionToken(token),
+		Scopes:    scopes,
+	}
+	if ttl > 0 {
+		expiresAt := s.now().Add(ttl)
+		apiToken.ExpiresAt = &expiresAt
+	}
+
+	res, err := s.tokens.Create(apiToken)
+	if err != nil {
+		return "", models.ApiToken{}, fmt.Errorf("error creating api token: %w", err)
+	}
+	return token, res, nil
+}
+
+// Authenticate возвращает владельца токена с правами, урезанными до скоупов токена
+func (s *ApiTokenService) Authenticate(token string) (Principal, error) {
+	if !strings.HasPrefix(token, ApiTokenPrefix) {
+		return Principal{}, ErrUnauthenticated
+	}
+	apiToken, err := s.tokens.GetByHash(hashSessionToken(token))
+	if err != nil {
+		return Principal{}, ErrUnauthenticated
+	}
+	if apiToken.RevokedAt != nil {
+		return Principal{}, ErrUnauthenticated
+	}
+	if apiToken.ExpiresAt != nil && !s.now().Before(*apiToken.ExpiresAt) {
+		return Principal{}, ErrUnauthenticated
+	}
+	user, err := s.auth.users.Get(apiToken.UserID)
+	if err != nil {
+		return Principal{}, ErrUnauthenticated
+	}
+	principal, err := s.auth.principal(user)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	var permissions []string
+	for _, permission := range principal.Permissions {
+		if scopesAllow(apiToken.Scopes, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+	principal.Permissions = permissions
+	principal.TokenID = apiToken.ID
+
+	if err := s.tokens.TouchLastUsed(apiToken.ID); err != nil {
+		slog.Warn(err.Error())
+	}
+	return principal, nil
+}
+
+// List возвращает токены пользователя
+func (s *ApiTokenService) List(userID int64) ([]models.ApiToken, error) {
+	res, err := s.tokens.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing api tokens: %w", err)
+	}
+	return res, nil
+}
+
+// Revoke отзывает токен пользователя
+func (s *ApiTokenService) Revoke(userID, id int64) error {
+	if err := s.tokens.Revoke(id, userID); err != nil {
+		return fmt.Errorf("error revoking api token: %w", err)
+	}
+	return nil
+}
+
+// ValidateScope проверяет формат скоупа "<действие>:<ресурс>"
+func ValidateScope(scope string) error {
+	action, resource, ok := strings.Cut(scope, ":")
+	if !ok {
+		return fmt.Errorf("invalid scope %q: expected <action>:<resource>", scope)
+	}
+	if !slices.Contains(ScopeActions, action) {
+		return fmt.Errorf("invalid scope %q: unknown action %q", scope, action)
+	}
+	if resource != ScopeAny && !slices.Contains(ScopeResources, resource) {
+		return fmt.Errorf("invalid scope %q: unknown resource %q", scope, resource)
+	}
+	return nil
+}
+
+// scopesAllow проверяет, разрешает ли хотя бы один скоуп право "<ресурс>:<действие>"
+func scopesAllow(scopes []string, permission string) bool {
+	resource, action, ok := strings.Cut(permission, ":")
+	if !ok {
+		return false
+	}
+	return slices.Contains(scopes, action+":"+resource) || slices.Contains(scopes, action+":"+ScopeAny)
+}
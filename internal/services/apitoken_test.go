@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// MockApiTokenStore мок-хранилище API-токенов для тестирования ApiTokenService
+type MockApiTokenStore struct {
+	tokens []models.ApiToken
+}
+
+func (m *MockApiTokenStore) GetByHash(hash string) (models.ApiToken, error) {
+	for _, t := range m.tokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+	return models.ApiToken{}, errors.New("api token not found")
+}
+
+func (m *MockApiTokenStore) ListByUser(userID int64) ([]models.ApiToken, error) {
+	var res []models.ApiToken
+	for _, t := range m.tokens {
+		if t.UserID == userID {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (m *MockApiTokenStore) Create(token models.ApiToken) (models.ApiToken, error) {
+	token.ID = int64(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return token, nil
+}
+
+func (m *MockApiTokenStore) Revoke(id, userID int64) error {
+	for i, t := range m.tokens {
+		if t.ID == id && t.UserID == userID && t.RevokedAt == nil {
+			now := time.Now()
+			m.tokens[i].RevokedAt = &now
+			return nil
+		}
+	}
+	return errors.New("api token not found")
+}
+
+func (m *MockApiTokenStore) TouchLastUsed(id int64) error {
+	for i, t := range m.tokens {
+		if t.ID == id {
+			now := time.Now()
+			m.tokens[i].LastUsedAt = &now
+		}
+	}
+	return nil
+}
+
+func newTestApiTokenService(t *testing.T, role string) (*ApiTokenService, models.User) {
+	t.Helper()
+	auth := NewAuthService(newMockUserStore(), newMockSessionStore(), newMockRoleStore(), time.Hour)
+	user, err := auth.CreateUser("admin", "secret-password", role)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	return NewApiTokenService(&MockApiTokenStore{}, auth), user
+}
+
+// TestApiTokenService_Issue тестирует валидацию при выпуске токена
+func TestApiTokenService_Issue(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenName string
+		scopes    []string
+		ttl       time.Duration
+		wantError bool
+		errorMsg  string
+	}{
+		{
+			name:      "Успешный выпуск",
+			tokenName: "frontend build",
+			scopes:    []string{"read:*", "write:tech"},
+			ttl:       time.Hour,
+		},
+		{
+			name:      "Пустое имя",
+			tokenName: "  ",
+			scopes:    []string{"read:*"},
+			wantError: true,
+			errorMsg:  "token name is required",
+		},
+		{
+			name:      "Без скоупов",
+			tokenName: "script",
+			wantError: true,
+			errorMsg:  "at least one scope is required",
+		},
+		{
+			name:      "Неизвестное действие",
+			tokenName: "script",
+			scopes:    []string{"manage:user"},
+			wantError: true,
+			errorMsg:  "unknown action",
+		},
+		{
+			name:      "Неизвестный ресурс",
+			tokenName: "script",
+			scopes:    []string{"write:user"},
+			wantError: true,
+			errorMsg:  "unknown resource",
+		},
+		{
+			name:      "Скоуп без двоеточия",
+			tokenName: "script",
+			scopes:    []string{"write"},
+			wantError: true,
+			errorMsg:  "expected <action>:<resource>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, user := newTestApiTokenService(t, RoleOwner)
+
+			token, apiToken, err := service.Issue(user.ID, tt.tokenName, tt.scopes, tt.ttl)
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("Ожидалась ошибка, но получили nil")
+				}
+				if !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Ожидалось сообщение об ошибке содержащее '%s', получили: %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if !contains(token, ApiTokenPrefix) || apiToken.TokenHash == token {
+				t.Errorf("В БД должен храниться хеш токена, а не сам токен")
+			}
+			if apiToken.ExpiresAt == nil {
+				t.Errorf("Ожидался срок действия токена")
+			}
+		})
+	}
+}
+
+// TestApiTokenService_Authenticate тестирует проверку токена и урезание прав скоупами
+func TestApiTokenService_Authenticate(t *testing.T) {
+	service, user := newTestApiTokenService(t, RoleEditor)
+
+	token, apiToken, err := service.Issue(user.ID, "script", []string{"write:wh"}, 0)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if apiToken.ExpiresAt != nil {
+		t.Errorf("Токен с ttl = 0 не должен истекать")
+	}
+
+	principal, err := service.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if principal.User.ID != user.ID || principal.TokenID != apiToken.ID {
+		t.Errorf("Ожидался пользователь %d и токен %d, получили %d и %d", user.ID, apiToken.ID, principal.User.ID, principal.TokenID)
+	}
+	if !principal.Can(PermWHWrite) {
+		t.Errorf("Скоуп write:wh должен давать право %s", PermWHWrite)
+	}
+	if principal.Can(PermEduWrite) {
+		t.Errorf("Право %s есть у роли, но не входит в скоупы токена", PermEduWrite)
+	}
+
+	// Скоуп не может расширить права роли
+	wide, _, err := service.Issue(user.ID, "wide", []string{"delete:*"}, 0)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	principal, err = service.Authenticate(wide)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if principal.Can(PermTagDelete) {
+		t.Errorf("Редактор не должен получать %s через скоуп токена", PermTagDelete)
+	}
+
+	if _, err := service.Authenticate("cvb_unknown"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Ожидалась ErrUnauthenticated для неизвестного токена, получили: %v", err)
+	}
+
+	if err := service.Revoke(user.ID, apiToken.ID); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if _, err := service.Authenticate(token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Отозванный токен должен быть недействителен, получили: %v", err)
+	}
+}
+
+// TestApiTokenService_Expired тестирует отказ по просроченному токену
+func TestApiTokenService_Expired(t *testing.T) {
+	service, user := newTestApiTokenService(t, RoleOwner)
+
+	token, _, err := service.Issue(user.ID, "script", []string{"read:*"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	if _, err := service.Authenticate(token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Ожидалась ErrUnauthenticated для просроченного токена, получили: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+const (
+	// MinPasswordLength минимальная длина пароля администратора
+	MinPasswordLength = 8
+	// DefaultSessionTTL время жизни сессии по умолчанию
+	DefaultSessionTTL = 24 * time.Hour
+)
+
+var (
+	// ErrInvalidCredentials неверный логин или пароль
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrUnauthenticated сессия отсутствует, просрочена или удалена
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// UserStore интерфейс хранилища пользователей
+type UserStore interface {
+	Get(id int64) (models.User, error)
+	GetByUsername(username string) (models.User, error)
+	Create(models.User) (models.User, error)
+}
+
+// SessionStore интерфейс хранилища сессий
+type SessionStore interface {
+	Get(id string) (models.Session, error)
+	Create(models.Session) (models.Session, error)
+	Delete(id string) error
+	DeleteExpired() (int64, error)
+}
+
+// AuthService сервис аутентификации администраторов
+type AuthService struct {
+	users    UserStore
+	sessions SessionStore
+	roles    RoleStore
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewAuthService создает новый экземпляр сервиса аутентификации
+func NewAuthService(users UserStore, sessions SessionStore, roles RoleStore, ttl time.Duration) *AuthService {
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	return &AuthService{
+		users:    users,
+		sessions: sessions,
+		roles:    roles,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// SessionTTL возвращает время жизни сессии
+func (s *AuthService) SessionTTL() time.Duration {
+	return s.ttl
+}
+
+// CreateUser создает пользователя с захешированным паролем и ролью
+func (s *AuthService) CreateUser(username, password, role string) (models.User, error) {
+	if username == "" {
+		return models.User{}, fmt.Errorf("username is required")
+	}
+	if !IsValidRole(role) {
+		return models.User{}, fmt.Errorf("unknown role %q", role)
+	}
+	if len(password) < MinPasswordLength {
+		return models.User{}, fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return models.User{}, fmt.Errorf("error hashing password: %w", err)
+	}
+	res, err := s.users.Create(models.User{
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         role,
+	})
+	if err != nil {
+		return models.User{}, fmt.Errorf("error creating user: %w", err)
+	}
+	return res, nil
+}
+
+// Login проверяет логин и пароль и создает новую сессию.
+// Возвращает токен для cookie; в БД хранится только его хеш.
+func (s *AuthService) Login(username, password string) (string, models.Session, error) {
+	user, err := s.users.GetByUsername(username)
+	if err != nil {
+		// Сравниваем с фиктивным хешем, чтобы время ответа не выдавало существование логина
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return "", models.Session{}, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return "", models.Session{}, ErrInvalidCredentials
+	}
+	// Входы редкие, поэтому просроченные сессии чистим здесь же
+	if _, err := s.DeleteExpiredSessions(); err != nil {
+		slog.Warn(err.Error())
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return "", models.Session{}, fmt.Errorf("error generating session token: %w", err)
+	}
+	session, err := s.sessions.Create(models.Session{
+		ID:        hashSessionToken(token),
+		UserID:    user.ID,
+		ExpiresAt: s.now().Add(s.ttl),
+	})
+	if err != nil {
+		return "", models.Session{}, fmt.Errorf("error creating session: %w", err)
+	}
+	return token, session, nil
+}
+
+// Authenticate возвращает пользователя и его права по токену сессии
+func (s *AuthService) Authenticate(token string) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+	session, err := s.sessions.Get(hashSessionToken(token))
+	if err != nil {
+		return Principal{}, ErrUnauthenticated
+	}
+	if !s.now().Before(session.ExpiresAt) {
+		return Principal{}, ErrUnauthenticated
+	}
+	user, err := s.users.Get(session.UserID)
+	if err != nil {
+		return Principal{}, ErrUnauthenticated
+	}
+	return s.principal(user)
+}
+
+// principal загружает роль и права пользователя
+func (s *AuthService) principal(user models.User) (Principal, error) {
+	role, err := s.roles.Get(user.Role)
+	if err != nil {
+		return Principal{}, fmt.Errorf("error getting role: %w", err)
+	}
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		return Principal{}, fmt.Errorf("error getting role permissions: %w", err)
+	}
+	return Principal{
+		User:        user,
+		Role:        role,
+		Permissions: permissions,
+	}, nil
+}
+
+// Logout удаляет сессию по токену
+func (s *AuthService) Logout(token string) error {
+	if token == "" {
+		return nil
+	}
+	if err := s.sessions.Delete(hashSessionToken(token)); err != nil {
+		return fmt.Errorf("error deleting session: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions удаляет просроченные сессии
+func (s *AuthService) DeleteExpiredSessions() (int64, error) {
+	res, err := s.sessions.DeleteExpired()
+	if err != nil {
+		return 0, fmt.Errorf("error deleting expired sessions: %w", err)
+	}
+	return res, nil
+}
+
+// dummyPasswordHash bcrypt-хеш, с которым сравнивается пароль несуществующего пользователя
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
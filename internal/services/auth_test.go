@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// MockUserStore мок-хранилище пользователей для тестирования AuthService
+type MockUserStore struct {
+	users  map[string]models.User
+	nextID int64
+}
+
+func newMockUserStore() *MockUserStore {
+	return &MockUserStore{users: map[string]models.User{}, nextID: 1}
+}
+
+func (m *MockUserStore) Get(id int64) (models.User, error) {
+	for _, u := range m.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return models.User{}, errors.New("user not found")
+}
+
+func (m *MockUserStore) GetByUsername(username string) (models.User, error) {
+	u, ok := m.users[username]
+	if !ok {
+		return models.User{}, errors.New("user not found")
+	}
+	return u, nil
+}
+
+func (m *MockUserStore) Create(user models.User) (models.User, error) {
+	if _, ok := m.users[user.Username]; ok {
+		return models.User{}, errors.New("duplicate username")
+	}
+	user.ID = m.nextID
+	m.nextID++
+	m.users[user.Username] = user
+	return user, nil
+}
+
+// MockSessionStore мок-хранилище сессий для тестирования AuthService
+type MockSessionStore struct {
+	sessions map[string]models.Session
+}
+
+func newMockSessionStore() *MockSessionStore {
+	return &MockSessionStore{sessions: map[string]models.Session{}}
+}
+
+func (m *MockSessionStore) Get(id string) (models.Session, error) {
+	s, ok := m.sessions[id]
+	if !ok {
+		return models.Session{}, errors.New("session not found")
+	}
+	return s, nil
+}
+
+func (m *MockSessionStore) Create(session models.Session) (models.Session, error) {
+	m.sessions[session.ID] = session
+	return session, nil
+}
+
+func (m *MockSessionStore) Delete(id string) error {
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *MockSessionStore) DeleteExpired() (int64, error) {
+	var n int64
+	for id, s := range m.sessions {
+		if !time.Now().Before(s.ExpiresAt) {
+			delete(m.sessions, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// MockRoleStore мок-хранилище ролей для тестирования AuthService
+type MockRoleStore struct {
+	roles       map[string]models.Role
+	permissions map[string][]string
+}
+
+func newMockRoleStore() *MockRoleStore {
+	return &MockRoleStore{
+		roles: map[string]models.Role{
+			RoleViewer: {Name: RoleViewer, Title: "Наблюдатель"},
+			RoleEditor: {Name: RoleEditor, Title: "Редактор"},
+			RoleOwner:  {Name: RoleOwner, Title: "Владелец"},
+		},
+		permissions: map[string][]string{
+			RoleEditor: {PermWHWrite, PermEduWrite},
+			RoleOwner:  {PermWHWrite, PermEduWrite, PermTagDelete, PermUserManage},
+		},
+	}
+}
+
+func (m *MockRoleStore) Get(name string) (models.Role, error) {
+	role, ok := m.roles[name]
+	if !ok {
+		return models.Role{}, errors.New("role not found")
+	}
+	return role, nil
+}
+
+func (m *MockRoleStore) Permissions(role string) ([]string, error) {
+	return m.permissions[role], nil
+}
+
+// TestAuthService_CreateUser тестирует метод CreateUser
+func TestAuthService_CreateUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		password  string
+		role      string
+		wantError bool
+		errorMsg  string
+	}{
+		{
+			name:     "Успешное создание пользователя",
+			username: "admin",
+			password: "secret-password",
+			role:     RoleOwner,
+		},
+		{
+			name:      "Пустой логин",
+			username:  "",
+			password:  "secret-password",
+			role:      RoleOwner,
+			wantError: true,
+			errorMsg:  "username is required",
+		},
+		{
+			name:      "Короткий пароль",
+			username:  "admin",
+			password:  "short",
+			role:      RoleOwner,
+			wantError: true,
+			errorMsg:  "password must be at least",
+		},
+		{
+			name:      "Неизвестная роль",
+			username:  "admin",
+			password:  "secret-password",
+			role:      "superuser",
+			wantError: true,
+			errorMsg:  "unknown role",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newMockUserStore()
+			service := NewAuthService(users, newMockSessionStore(), newMockRoleStore(), time.Hour)
+
+			user, err := service.CreateUser(tt.username, tt.password, tt.role)
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("Ожидалась ошибка, но получили nil")
+				}
+				if !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Ожидалось сообщение об ошибке содержащее '%s', получили: %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if user.PasswordHash == tt.password {
+				t.Errorf("Пароль сохранен в открытом виде")
+			}
+			if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(tt.password)); err != nil {
+				t.Errorf("Хеш не соответствует паролю: %v", err)
+			}
+		})
+	}
+}
+
+// TestAuthService_LoginAuthenticateLogout тестирует полный цикл сессии
+func TestAuthService_LoginAuthenticateLogout(t *testing.T) {
+	users := newMockUserStore()
+	sessions := newMockSessionStore()
+	service := NewAuthService(users, sessions, newMockRoleStore(), time.Hour)
+
+	created, err := service.CreateUser("admin", "secret-password", RoleEditor)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	if _, _, err := service.Login("admin", "wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Ожидалась ErrInvalidCredentials для неверного пароля, получили: %v", err)
+	}
+	if _, _, err := service.Login("unknown", "secret-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Ожидалась ErrInvalidCredentials для неизвестного логина, получили: %v", err)
+	}
+
+	token, session, err := service.Login("admin", "secret-password")
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if token == "" || session.ID == token {
+		t.Errorf("В БД должен храниться хеш токена, а не сам токен")
+	}
+
+	principal, err := service.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if principal.User.ID != created.ID {
+		t.Errorf("Ожидался ID = %d, получили %d", created.ID, principal.User.ID)
+	}
+	if principal.Role.Title != "Редактор" {
+		t.Errorf("Ожидалась роль 'Редактор', получили %q", principal.Role.Title)
+	}
+	if !principal.Can(PermWHWrite) {
+		t.Errorf("Редактор должен иметь право %s", PermWHWrite)
+	}
+	if principal.Can(PermTagDelete) {
+		t.Errorf("Редактор не должен иметь право %s", PermTagDelete)
+	}
+
+	if _, err := service.Authenticate("bad-token"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Ожидалась ErrUnauthenticated, получили: %v", err)
+	}
+
+	if err := service.Logout(token); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if _, err := service.Authenticate(token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("После выхода сессия должна быть недействительна, получили: %v", err)
+	}
+}
+
+// TestAuthService_ExpiredSession тестирует отказ по просроченной сессии
+func TestAuthService_ExpiredSession(t *testing.T) {
+	users := newMockUserStore()
+	sessions := newMockSessionStore()
+	service := NewAuthService(users, sessions, newMockRoleStore(), time.Hour)
+
+	if _, err := service.CreateUser("admin", "secret-password", RoleEditor); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	token, _, err := service.Login("admin", "secret-password")
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	if _, err := service.Authenticate(token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Ожидалась ErrUnauthenticated для просроченной сессии, получили: %v", err)
+	}
+}
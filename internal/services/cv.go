@@ -0,0 +1,152 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+)
+
+// CV резюме целиком: профиль, история работы, образование и технологии по тегам
+type CV struct {
+	Profile      Profile                       `json:"profile"`
+	WorkHistory  []WorkHistoryWithTechnologies `json:"workHistory"`
+	Education    []models.Education            `json:"education"`
+	Technologies []TechnologyGroup             `json:"technologies"`
+}
+
+// Profile данные владельца резюме, задаются переменными окружения CV_*
+type Profile struct {
+	Name     string   `json:"name"`
+	Position string   `json:"position"`
+	About    string   `json:"about"`
+	Email    string   `json:"email,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Links    []string `json:"links,omitempty"`
+}
+
+// TechnologyGroup технологии с одним тегом. Технология с несколькими тегами входит
+// в несколько групп, технологии без тегов собраны в группу с Tag == nil.
+type TechnologyGroup struct {
+	Tag          *models.Tag         `json:"tag"`
+	Technologies []models.Technology `json:"technologies"`
+}
+
+// CVService собирает резюме целиком из сервисов истории работы, образования и технологий
+type CVService struct {
+	profile      Profile
+	workHistory  *WorkHistoryService
+	education    *EducationService
+	technologies *TechService
+}
+
+// NewCVService создает новый экземпляр сервиса резюме
+func NewCVService(profile Profile, wh *WorkHistoryService, edu *EducationService, tech *TechService) *CVService {
+	return &CVService{
+		profile:      profile,
+		workHistory:  wh,
+		education:    edu,
+		technologies: tech,
+	}
+}
+
+// Get собирает резюме: история работы от последнего места, образование от последнего года,
+// технологии по алфавиту, сгруппированные по тегам. Связи загружаются одним запросом на уровень.
+func (s *CVService) Get() (CV, error) {
+	cv := CV{Profile: s.profile}
+
+	workHistory, err := s.workHistory.List(allRows(
+		entityreqdecorator.SortBy{Field: "periodStart", Order: "DESC"},
+		entityreqdecorator.SortBy{Field: "id", Order: "DESC"},
+	))
+	if err != nil {
+		return CV{}, fmt.Errorf("error getting cv work history: %w", err)
+	}
+	whIDs := make([]int64, len(workHistory.Content))
+	for i, wh := range workHistory.Content {
+		whIDs[i] = wh.ID
+	}
+	whTechnologies, err := s.workHistory.TechnologiesByWorkHistory(whIDs)
+	if err != nil {
+		return CV{}, fmt.Errorf("error getting cv work history: %w", err)
+	}
+	cv.WorkHistory = make([]WorkHistoryWithTechnologies, len(workHistory.Content))
+	for i, wh := range workHistory.Content {
+		cv.WorkHistory[i] = WorkHistoryWithTechnologies{WorkHistory: wh, Technologies: orEmpty(whTechnologies[wh.ID])}
+	}
+
+	education, err := s.education.List(allRows(
+		entityreqdecorator.SortBy{Field: "year", Order: "DESC"},
+		entityreqdecorator.SortBy{Field: "id", Order: "DESC"},
+	))
+	if err != nil {
+		return CV{}, fmt.Errorf("error getting cv education: %w", err)
+	}
+	cv.Education = orEmpty(education.Content)
+
+	technologies, err := s.technologies.List(allRows(
+		entityreqdecorator.SortBy{Field: "title", Order: "ASC"},
+	))
+	if err != nil {
+		return CV{}, fmt.Errorf("error getting cv technologies: %w", err)
+	}
+	techIDs := make([]int64, len(technologies.Content))
+	for i, tech := range technologies.Content {
+		techIDs[i] = tech.ID
+	}
+	tags, err := s.technologies.TagsByTechnology(techIDs)
+	if err != nil {
+		return CV{}, fmt.Errorf("error getting cv technologies: %w", err)
+	}
+	cv.Technologies = groupByTag(technologies.Content, tags)
+
+	return cv, nil
+}
+
+// allRows запрос всех записей списка без пагинации
+func allRows(sort ...entityreqdecorator.SortBy) entityreqdecorator.PagebleRq {
+	return entityreqdecorator.PagebleRq{Page: 1, Sort: sort}
+}
+
+// groupByTag группирует технологии по тегам в порядке имен тегов, сохраняя порядок технологий.
+// Технологии без тегов идут последней группой.
+func groupByTag(technologies []models.Technology, tags map[int64][]models.Tag) []TechnologyGroup {
+	groups := make(map[int64]*TechnologyGroup)
+	untagged := TechnologyGroup{}
+	for _, tech := range technologies {
+		if len(tags[tech.ID]) == 0 {
+			untagged.Technologies = append(untagged.Technologies, tech)
+			continue
+		}
+		for _, tag := range tags[tech.ID] {
+			group, ok := groups[tag.ID]
+			if !ok {
+				group = &TechnologyGroup{Tag: &tag}
+				groups[tag.ID] = group
+			}
+			group.Technologies = append(group.Technologies, tech)
+		}
+	}
+
+	result := make([]TechnologyGroup, 0, len(groups)+1)
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	slices.SortFunc(result, func(a, b TechnologyGroup) int {
+		return cmp.Or(cmp.Compare(a.Tag.Name, b.Tag.Name), cmp.Compare(a.Tag.ID, b.Tag.ID))
+	})
+	if len(untagged.Technologies) > 0 {
+		result = append(result, untagged)
+	}
+	return result
+}
+
+// orEmpty заменяет nil пустым списком, чтобы в JSON был [] вместо null
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+)
+
+// TestCVService_Get проверяет сборку резюме из сервисов
+func TestCVService_Get(t *testing.T) {
+	var whSort, eduSort []entityreqdecorator.SortBy
+	whRepo := &MockWorkHistoryRepo{
+		ListFunc: func(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.WorkHistory], error) {
+			whSort = req.Sort
+			return entityreqdecorator.PagebleRs[models.WorkHistory]{
+				Content: []models.WorkHistory{{ID: 2, Name: "Яндекс"}, {ID: 1, Name: "Стартап"}},
+			}, nil
+		},
+		TechnologiesByWorkHistoryFunc: func(whIDs []int64) (map[int64][]models.Technology, error) {
+			return map[int64][]models.Technology{2: {{ID: 10, Title: "Go"}}}, nil
+		},
+	}
+	eduRepo := &MockEducationRepo{
+		ListFunc: func(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Education], error) {
+			eduSort = req.Sort
+			return entityreqdecorator.PagebleRs[models.Education]{}, nil
+		},
+	}
+	techRepo := &MockTechRepo{
+		ListFunc: func(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Technology], error) {
+			return entityreqdecorator.PagebleRs[models.Technology]{
+				Content: []models.Technology{{ID: 10, Title: "Go"}, {ID: 11, Title: "PostgreSQL"}, {ID: 12, Title: "Vim"}},
+			}, nil
+		},
+		TagsByTechnologyFunc: func(techIDs []int64) (map[int64][]models.Tag, error) {
+			backend := models.Tag{ID: 1, Name: "backend", HexColor: "#00ff00"}
+			databases := models.Tag{ID: 2, Name: "databases", HexColor: "#0000ff"}
+			return map[int64][]models.Tag{10: {backend}, 11: {databases, backend}}, nil
+		},
+	}
+	service := NewCVService(Profile{Name: "Максим"},
+		NewWorkHistoryService(whRepo), NewEducationService(eduRepo), NewTechService(techRepo))
+
+	cv, err := service.Get()
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	if cv.Profile.Name != "Максим" {
+		t.Errorf("Ожидался профиль из конфигурации, получили %+v", cv.Profile)
+	}
+	if len(whSort) == 0 || whSort[0] != (entityreqdecorator.SortBy{Field: "periodStart", Order: "DESC"}) {
+		t.Errorf("История работы должна сортироваться по periodStart, получили %v", whSort)
+	}
+	if len(eduSort) == 0 || eduSort[0] != (entityreqdecorator.SortBy{Field: "year", Order: "DESC"}) {
+		t.Errorf("Образование должно сортироваться по year, получили %v", eduSort)
+	}
+	if len(cv.WorkHistory) != 2 || len(cv.WorkHistory[0].Technologies) != 1 || cv.WorkHistory[1].Technologies == nil {
+		t.Errorf("Ожидались технологии у каждой записи истории работы, получили %+v", cv.WorkHistory)
+	}
+	if cv.Education == nil {
+		t.Errorf("Пустое образование должно быть пустым списком")
+	}
+
+	// backend: Go, PostgreSQL; databases: PostgreSQL; без тега: Vim
+	if len(cv.Technologies) != 3 {
+		t.Fatalf("Ожидалось 3 группы технологий, получили %+v", cv.Technologies)
+	}
+	if g := cv.Technologies[0]; g.Tag.Name != "backend" || g.Tag.HexColor != "#00ff00" || len(g.Technologies) != 2 || g.Technologies[0].Title != "Go" {
+		t.Errorf("Первая группа = %+v, ожидалась backend с Go и PostgreSQL", g)
+	}
+	if g := cv.Technologies[1]; g.Tag.Name != "databases" || len(g.Technologies) != 1 {
+		t.Errorf("Вторая группа = %+v, ожидалась databases с PostgreSQL", g)
+	}
+	if g := cv.Technologies[2]; g.Tag != nil || len(g.Technologies) != 1 || g.Technologies[0].Title != "Vim" {
+		t.Errorf("Последняя группа = %+v, ожидались технологии без тега", g)
+	}
+}
+
+// TestCVService_GetError проверяет, что ошибка сервиса прерывает сборку резюме
+func TestCVService_GetError(t *testing.T) {
+	techRepo := &MockTechRepo{
+		ListFunc: func(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Technology], error) {
+			return entityreqdecorator.PagebleRs[models.Technology]{}, errors.New("database error")
+		},
+	}
+	service := NewCVService(Profile{},
+		NewWorkHistoryService(&MockWorkHistoryRepo{}), NewEducationService(&MockEducationRepo{}), NewTechService(techRepo))
+
+	if _, err := service.Get(); err == nil || !contains(err.Error(), "error getting cv technologies") {
+		t.Errorf("Ожидалась ошибка получения технологий, получили: %v", err)
+	}
+}
@@ -23,6 +23,7 @@ type EducationWriter interface {
 type EducationReader interface {
 	Get(id int64) (models.Education, error)
 	List(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Education], error)
+	Schema() entityreqdecorator.Schema
 }
 
 // EducationManager объединяет все интерфейсы для работы с образованием
@@ -68,6 +69,11 @@ func (s *EducationService) Delete(id int64) (int64, error) {
 	return res, nil
 }
 
+// Schema возвращает схему полей образования для разбора параметра fields
+func (s *EducationService) Schema() entityreqdecorator.Schema {
+	return s.repo.Schema()
+}
+
 // Get получает одну запись образования по ID
 func (s *EducationService) Get(id int64) (models.Education, error) {
 	if id == 0 {
@@ -20,6 +20,10 @@ type MockEducationRepo struct {
 	DeleteListFunc func([]int64) ([]int64, error)
 }
 
+func (m *MockEducationRepo) Schema() entityreqdecorator.Schema {
+	return nil
+}
+
 func (m *MockEducationRepo) Get(id int64) (models.Education, error) {
 	if m.GetFunc != nil {
 		return m.GetFunc(id)
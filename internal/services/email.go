@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// Параметры обработки очереди писем по умолчанию
+const (
+	DefaultEmailMaxAttempts  = 5
+	DefaultEmailBatchSize    = 20
+	DefaultEmailPollInterval = 30 * time.Second
+	// emailLease время, на которое захваченное письмо скрывается от других обработчиков
+	emailLease = 5 * time.Minute
+	// emailMaxBackoff максимальная пауза между попытками отправки
+	emailMaxBackoff = time.Hour
+)
+
+// Mailer отправляет письмо
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPMailer отправляет письма через SMTP-сервер.
+// Если сервер поддерживает STARTTLS, соединение шифруется.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Send реализует Mailer
+func (m SMTPMailer) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	if err := smtp.SendMail(addr, auth, m.From, []string{to}, buildMessage(m.From, to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// buildMessage собирает письмо в формате RFC 5322 с текстом в UTF-8
+func buildMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	// Кодирование по RFC 2047 также не дает переводам строк из темы попасть в заголовки
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// EmailQueueStore интерфейс хранилища очереди писем
+type EmailQueueStore interface {
+	Enqueue(models.EmailQueue) (models.EmailQueue, error)
+	Due(limit int, maxAttempts int, lease time.Duration) ([]models.EmailQueue, error)
+	MarkSent(id int64) error
+	MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error
+}
+
+// EmailService ставит уведомления в очередь и отправляет их в фоне с повторными попытками
+type EmailService struct {
+	queue       EmailQueueStore
+	mailer      Mailer
+	notifyTo    string
+	maxAttempts int
+	now         func() time.Time
+}
+
+// NewEmailService создает сервис уведомлений, отправляющий письма на адрес notifyTo
+func NewEmailService(queue EmailQueueStore, mailer Mailer, notifyTo string) *EmailService {
+	return &EmailService{
+		queue:       queue,
+		mailer:      mailer,
+		notifyTo:    notifyTo,
+		maxAttempts: DefaultEmailMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// FeedbackCreated ставит в очередь уведомление о новом сообщении обратной связи
+func (s *EmailService) FeedbackCreated(feedback models.Feedback) error {
+	body := fmt.Sprintf("Новое сообщение #%d\n\nОт: %s\nКонтакт: %s\n\n%s\n",
+		feedback.ID, feedback.AuthorName, feedback.Contact, feedback.Message)
+	_, err := s.queue.Enqueue(models.EmailQueue{
+		Recipient: s.notifyTo,
+		Subject:   "Новое сообщение с сайта от " + feedback.AuthorName,
+		Body:      body,
+	})
+	if err != nil {
+		return fmt.Errorf("error enqueueing feedback notification: %w", err)
+	}
+	return nil
+}
+
+// ProcessQueue отправляет письма, время попытки которых наступило, и возвращает число отправленных
+func (s *EmailService) ProcessQueue() (int, error) {
+	emails, err := s.queue.Due(DefaultEmailBatchSize, s.maxAttempts, emailLease)
+	if err != nil {
+		return 0, fmt.Errorf("error getting due emails: %w", err)
+	}
+
+	sent := 0
+	for _, email := range emails {
+		if err := s.mailer.Send(email.Recipient, email.Subject, email.Body); err != nil {
+			attempt := int(email.Attempts) + 1
+			slog.Warn("failed to send email", "id", email.ID, "attempt", attempt, "error", err)
+			if err := s.queue.MarkFailed(email.ID, err.Error(), s.now().Add(emailBackoff(attempt))); err != nil {
+				return sent, fmt.Errorf("error updating email: %w", err)
+			}
+			continue
+		}
+		if err := s.queue.MarkSent(email.ID); err != nil {
+			return sent, fmt.Errorf("error updating email: %w", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Run обрабатывает очередь каждые interval до отмены ctx
+func (s *EmailService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ProcessQueue(); err != nil {
+			slog.Error(err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// emailBackoff пауза перед следующей попыткой: 1, 2, 4... минуты, но не больше часа
+func emailBackoff(attempt int) time.Duration {
+	backoff := time.Minute << (attempt - 1)
+	if backoff <= 0 || backoff > emailMaxBackoff {
+		return emailMaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// fakeSMTPServer минимальный SMTP-сервер для тестов, сохраняющий полученные письма
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+	rcpts    []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Не удалось запустить SMTP-сервер: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost fake smtp")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.TrimSpace(line))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// TestSMTPMailer_Send тестирует отправку письма на локальный SMTP-сервер
+func TestSMTPMailer_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	mailer := SMTPMailer{Host: "127.0.0.1", Port: server.port(), From: "cv@example.com"}
+
+	err := mailer.Send("owner@example.com", "Новое сообщение\r\nBcc: victim@example.com", "Привет!\nВторая строка")
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 1 {
+		t.Fatalf("Ожидалось 1 письмо, получили %d", len(server.messages))
+	}
+	msg := server.messages[0]
+	if !contains(server.rcpts[0], "owner@example.com") {
+		t.Errorf("Ожидался получатель owner@example.com, получили %s", server.rcpts[0])
+	}
+	if !contains(msg, "Subject: =?utf-8?q?") {
+		t.Errorf("Тема с кириллицей должна быть закодирована по RFC 2047:\n%s", msg)
+	}
+	if contains(msg, "\r\nBcc:") {
+		t.Errorf("Перевод строки в теме не должен добавлять заголовки:\n%s", msg)
+	}
+	if !contains(msg, "Привет!\r\nВторая строка") {
+		t.Errorf("Тело письма не найдено:\n%s", msg)
+	}
+}
+
+// MockEmailQueue мок-очередь писем для тестирования EmailService
+type MockEmailQueue struct {
+	emails []models.EmailQueue
+	sent   []int64
+	failed map[int64]string
+}
+
+func (m *MockEmailQueue) Enqueue(email models.EmailQueue) (models.EmailQueue, error) {
+	email.ID = int64(len(m.emails) + 1)
+	m.emails = append(m.emails, email)
+	return email, nil
+}
+
+func (m *MockEmailQueue) Due(limit int, maxAttempts int, lease time.Duration) ([]models.EmailQueue, error) {
+	return m.emails, nil
+}
+
+func (m *MockEmailQueue) MarkSent(id int64) error {
+	m.sent = append(m.sent, id)
+	return nil
+}
+
+func (m *MockEmailQueue) MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	if m.failed == nil {
+		m.failed = map[int64]string{}
+	}
+	m.failed[id] = lastError
+	return nil
+}
+
+// MockMailer мок-отправщик, который отказывает получателям из списка
+type MockMailer struct {
+	failFor string
+}
+
+func (m *MockMailer) Send(to, subject, body string) error {
+	if to == m.failFor {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// TestEmailService_ProcessQueue тестирует отправку и повторные попытки
+func TestEmailService_ProcessQueue(t *testing.T) {
+	queue := &MockEmailQueue{}
+	service := NewEmailService(queue, &MockMailer{failFor: "broken@example.com"}, "owner@example.com")
+
+	if err := service.FeedbackCreated(models.Feedback{ID: 7, AuthorName: "Иван", Contact: "ivan@example.com", Message: "Есть вакансия"}); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	queue.Enqueue(models.EmailQueue{Recipient: "broken@example.com", Subject: "s", Body: "b"})
+
+	if !contains(queue.emails[0].Body, "Есть вакансия") || queue.emails[0].Recipient != "owner@example.com" {
+		t.Errorf("Уведомление сформировано неверно: %+v", queue.emails[0])
+	}
+
+	sent, err := service.ProcessQueue()
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if sent != 1 || len(queue.sent) != 1 || queue.sent[0] != 1 {
+		t.Errorf("Ожидалось одно отправленное письмо с ID 1, получили %d %v", sent, queue.sent)
+	}
+	if queue.failed[2] != "connection refused" {
+		t.Errorf("Ожидалась ошибка отправки для письма 2, получили %v", queue.failed)
+	}
+}
+
+// TestEmailBackoff тестирует рост паузы между попытками
+func TestEmailBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Minute},
+		{attempt: 2, want: 2 * time.Minute},
+		{attempt: 4, want: 8 * time.Minute},
+		{attempt: 10, want: time.Hour},
+		{attempt: 100, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := emailBackoff(tt.attempt); got != tt.want {
+			t.Errorf("emailBackoff(%d) = %v, ожидалось %v", tt.attempt, got, tt.want)
+		}
+	}
+}
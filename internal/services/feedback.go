@@ -1,7 +1,257 @@
 package services
 
-type Feedback struct{}
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
-func NewFeedback() *Feedback {
-	return &Feedback{}
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+)
+
+// Статусы сообщений обратной связи
+const (
+	FeedbackStatusNew      = "new"
+	FeedbackStatusRead     = "read"
+	FeedbackStatusReplied  = "replied"
+	FeedbackStatusSpam     = "spam"
+	FeedbackStatusArchived = "archived"
+)
+
+// FeedbackStatuses список статусов в порядке отображения
+var FeedbackStatuses = []string{
+	FeedbackStatusNew,
+	FeedbackStatusRead,
+	FeedbackStatusReplied,
+	FeedbackStatusSpam,
+	FeedbackStatusArchived,
+}
+
+// feedbackTransitions допустимые переходы между статусами
+var feedbackTransitions = map[string][]string{
+	FeedbackStatusNew:      {FeedbackStatusRead, FeedbackStatusReplied, FeedbackStatusSpam, FeedbackStatusArchived},
+	FeedbackStatusRead:     {FeedbackStatusNew, FeedbackStatusReplied, FeedbackStatusSpam, FeedbackStatusArchived},
+	FeedbackStatusReplied:  {FeedbackStatusArchived},
+	FeedbackStatusSpam:     {FeedbackStatusNew, FeedbackStatusArchived},
+	FeedbackStatusArchived: {FeedbackStatusRead},
+}
+
+// Ограничения длины полей сообщения обратной связи
+const (
+	MaxFeedbackAuthorLength  = 100
+	MaxFeedbackContactLength = 200
+	MaxFeedbackMessageLength = 5000
+	// maxUserAgentLength User-Agent обрезается, чтобы не хранить произвольно длинные заголовки
+	maxUserAgentLength = 512
+)
+
+var (
+	// ErrInvalidFeedback сообщение не прошло валидацию
+	ErrInvalidFeedback = errors.New("invalid feedback")
+	// ErrInvalidStatusTransition неизвестный статус или недопустимый переход
+	ErrInvalidStatusTransition = errors.New("invalid feedback status transition")
+)
+
+// FeedbackWriter интерфейс для создания сообщений обратной связи и смены их статуса
+type FeedbackWriter interface {
+	Create(models.Feedback) (models.Feedback, error)
+	UpdateStatus(ids []int64, status string, from []string) ([]int64, error)
+}
+
+// FeedbackReader интерфейс для чтения сообщений обратной связи
+type FeedbackReader interface {
+	Get(id int64) (models.Feedback, error)
+	List(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Feedback], error)
+	CountByStatus(status string) (int, error)
+	Schema() entityreqdecorator.Schema
+}
+
+// FeedbackManager объединяет все интерфейсы для работы с обратной связью
+type FeedbackManager interface {
+	FeedbackReader
+	FeedbackWriter
+}
+
+// FeedbackNotifier получает уведомления о новых сообщениях, например для отправки письма владельцу
+type FeedbackNotifier interface {
+	FeedbackCreated(models.Feedback) error
+}
+
+// FeedbackService сервис для работы с обратной связью
+type FeedbackService struct {
+	repo         FeedbackManager
+	spamCheckers []SpamChecker
+	notifier     FeedbackNotifier
+}
+
+// NewFeedbackService создает новый экземпляр сервиса обратной связи.
+// spamCheckers проверяют отправки из публичной формы, см. Submit.
+func NewFeedbackService(repo FeedbackManager, spamCheckers ...SpamChecker) *FeedbackService {
+	return &FeedbackService{
+		repo:         repo,
+		spamCheckers: spamCheckers,
+	}
+}
+
+// Schema возвращает схему полей сообщений обратной связи для разбора параметра fields
+func (s *FeedbackService) Schema() entityreqdecorator.Schema {
+	return s.repo.Schema()
+}
+
+// Get получает одно сообщение по ID
+func (s *FeedbackService) Get(id int64) (models.Feedback, error) {
+	if id == 0 {
+		return models.Feedback{}, fmt.Errorf("invalid feedback ID: %d", id)
+	}
+	res, err := s.repo.Get(id)
+	if err != nil {
+		return models.Feedback{}, fmt.Errorf("error getting feedback: %w", err)
+	}
+	return res, nil
+}
+
+// List получает список сообщений с пагинацией и фильтрацией
+func (s *FeedbackService) List(r entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Feedback], error) {
+	res, err := s.repo.List(r)
+	if err != nil {
+		return entityreqdecorator.PagebleRs[models.Feedback]{}, fmt.Errorf("error in getting list from Feedback repo: %w", err)
+	}
+	return res, nil
+}
+
+// SetNotifier подключает уведомления о новых сообщениях
+func (s *FeedbackService) SetNotifier(notifier FeedbackNotifier) {
+	s.notifier = notifier
+}
+
+// Create проверяет и сохраняет сообщение посетителя со статусом new
+func (s *FeedbackService) Create(feedback models.Feedback) (models.Feedback, error) {
+	return s.create(feedback, FeedbackStatusNew)
+}
+
+// Submit сохраняет отправку публичной формы. Если хотя бы одна проверка сочла ее спамом,
+// сообщение сохраняется со статусом spam, а не отклоняется, чтобы бот не узнал о срабатывании.
+func (s *FeedbackService) Submit(submission FeedbackSubmission) (models.Feedback, error) {
+	status := FeedbackStatusNew
+	for _, checker := range s.spamCheckers {
+		if reason, spam := checker.CheckSpam(submission); spam {
+			slog.Info("feedback marked as spam", "reason", reason, "ip", submission.Feedback.SourceIp)
+			status = FeedbackStatusSpam
+			break
+		}
+	}
+	return s.create(submission.Feedback, status)
+}
+
+func (s *FeedbackService) create(feedback models.Feedback, status string) (models.Feedback, error) {
+	feedback.AuthorName = strings.TrimSpace(feedback.AuthorName)
+	feedback.Contact = strings.TrimSpace(feedback.Contact)
+	feedback.Message = strings.TrimSpace(feedback.Message)
+
+	if err := validateFeedback(feedback); err != nil {
+		return models.Feedback{}, err
+	}
+	feedback.Status = status
+	feedback.UserAgent = truncate(feedback.UserAgent, maxUserAgentLength)
+
+	res, err := s.repo.Create(feedback)
+	if err != nil {
+		return models.Feedback{}, fmt.Errorf("error creating feedback: %w", err)
+	}
+	// О спаме не уведомляем; ошибка уведомления не должна терять уже сохраненное сообщение
+	if s.notifier != nil && res.Status == FeedbackStatusNew {
+		if err := s.notifier.FeedbackCreated(res); err != nil {
+			slog.Error(err.Error())
+		}
+	}
+	return res, nil
+}
+
+// ChangeStatus переводит сообщение в новый статус, если переход допустим
+func (s *FeedbackService) ChangeStatus(id int64, status string) (models.Feedback, error) {
+	if !slices.Contains(FeedbackStatuses, status) {
+		return models.Feedback{}, fmt.Errorf("%w: unknown status %q", ErrInvalidStatusTransition, status)
+	}
+	feedback, err := s.Get(id)
+	if err != nil {
+		return models.Feedback{}, err
+	}
+	if feedback.Status == status {
+		return feedback, nil
+	}
+	if !CanChangeFeedbackStatus(feedback.Status, status) {
+		return models.Feedback{}, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, feedback.Status, status)
+	}
+	updated, err := s.repo.UpdateStatus([]int64{id}, status, []string{feedback.Status})
+	if err != nil {
+		return models.Feedback{}, fmt.Errorf("error updating feedback status: %w", err)
+	}
+	if len(updated) == 0 {
+		return models.Feedback{}, fmt.Errorf("feedback with id %d was changed concurrently", id)
+	}
+	feedback.Status = status
+	return feedback, nil
+}
+
+// BulkChangeStatus переводит сообщения в новый статус. Сообщения, для которых переход
+// недопустим, пропускаются; возвращаются ID обновленных.
+func (s *FeedbackService) BulkChangeStatus(ids []int64, status string) ([]int64, error) {
+	if !slices.Contains(FeedbackStatuses, status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidStatusTransition, status)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var from []string
+	for _, st := range FeedbackStatuses {
+		if CanChangeFeedbackStatus(st, status) {
+			from = append(from, st)
+		}
+	}
+	res, err := s.repo.UpdateStatus(ids, status, from)
+	if err != nil {
+		return nil, fmt.Errorf("error updating feedback status: %w", err)
+	}
+	return res, nil
+}
+
+// CountUnread возвращает количество сообщений в статусе new
+func (s *FeedbackService) CountUnread() (int, error) {
+	res, err := s.repo.CountByStatus(FeedbackStatusNew)
+	if err != nil {
+		return 0, fmt.Errorf("error counting unread feedback: %w", err)
+	}
+	return res, nil
+}
+
+// CanChangeFeedbackStatus проверяет, допустим ли переход между статусами
+func CanChangeFeedbackStatus(from, to string) bool {
+	return slices.Contains(feedbackTransitions[from], to)
+}
+
+func validateFeedback(feedback models.Feedback) error {
+	if feedback.AuthorName == "" || feedback.Contact == "" || feedback.Message == "" {
+		return fmt.Errorf("%w: authorName, contact and message are required fields", ErrInvalidFeedback)
+	}
+	if utf8.RuneCountInString(feedback.AuthorName) > MaxFeedbackAuthorLength {
+		return fmt.Errorf("%w: authorName must be at most %d characters long", ErrInvalidFeedback, MaxFeedbackAuthorLength)
+	}
+	if utf8.RuneCountInString(feedback.Contact) > MaxFeedbackContactLength {
+		return fmt.Errorf("%w: contact must be at most %d characters long", ErrInvalidFeedback, MaxFeedbackContactLength)
+	}
+	if utf8.RuneCountInString(feedback.Message) > MaxFeedbackMessageLength {
+		return fmt.Errorf("%w: message must be at most %d characters long", ErrInvalidFeedback, MaxFeedbackMessageLength)
+	}
+	return nil
+}
+
+// truncate обрезает строку до max символов
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
 }
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
+)
+
+// MockFeedbackRepo мок-репозиторий для тестирования FeedbackService
+type MockFeedbackRepo struct {
+	GetFunc           func(id int64) (models.Feedback, error)
+	ListFunc          func(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Feedback], error)
+	CreateFunc        func(models.Feedback) (models.Feedback, error)
+	UpdateStatusFunc  func(ids []int64, status string, from []string) ([]int64, error)
+	CountByStatusFunc func(status string) (int, error)
+}
+
+func (m *MockFeedbackRepo) Schema() entityreqdecorator.Schema {
+	return nil
+}
+
+func (m *MockFeedbackRepo) Get(id int64) (models.Feedback, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	return models.Feedback{}, nil
+}
+
+func (m *MockFeedbackRepo) List(req entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Feedback], error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(req)
+	}
+	return entityreqdecorator.PagebleRs[models.Feedback]{}, nil
+}
+
+func (m *MockFeedbackRepo) Create(feedback models.Feedback) (models.Feedback, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(feedback)
+	}
+	return feedback, nil
+}
+
+func (m *MockFeedbackRepo) UpdateStatus(ids []int64, status string, from []string) ([]int64, error) {
+	if m.UpdateStatusFunc != nil {
+		return m.UpdateStatusFunc(ids, status, from)
+	}
+	return ids, nil
+}
+
+func (m *MockFeedbackRepo) CountByStatus(status string) (int, error) {
+	if m.CountByStatusFunc != nil {
+		return m.CountByStatusFunc(status)
+	}
+	return 0, nil
+}
+
+// TestFeedbackService_Get тестирует метод Get
+func TestFeedbackService_Get(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        int64
+		mockError error
+		wantError bool
+		errorMsg  string
+	}{
+		{
+			name: "Успешное получение сообщения",
+			id:   1,
+		},
+		{
+			name:      "Нулевой ID",
+			id:        0,
+			wantError: true,
+			errorMsg:  "invalid feedback ID",
+		},
+		{
+			name:      "Ошибка репозитория",
+			id:        999,
+			mockError: errors.New("feedback with id 999 not found"),
+			wantError: true,
+			errorMsg:  "error getting feedback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockFeedbackRepo{
+				GetFunc: func(id int64) (models.Feedback, error) {
+					return models.Feedback{ID: id}, tt.mockError
+				},
+			}
+			service := NewFeedbackService(mockRepo)
+
+			result, err := service.Get(tt.id)
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("Ожидалась ошибка, но получили nil")
+				}
+				if !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Ожидалось сообщение об ошибке содержащее '%s', получили: %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if result.ID != tt.id {
+				t.Errorf("Ожидался ID = %d, получили %d", tt.id, result.ID)
+			}
+		})
+	}
+}
+
+// TestFeedbackService_Create тестирует валидацию и создание сообщения
+func TestFeedbackService_Create(t *testing.T) {
+	valid := models.Feedback{
+		AuthorName: "  Иван  ",
+		Contact:    "ivan@example.com",
+		Message:    "Здравствуйте! Есть вакансия.",
+		Status:     "archived",
+	}
+	with := func(mod func(*models.Feedback)) models.Feedback {
+		f := valid
+		mod(&f)
+		return f
+	}
+
+	tests := []struct {
+		name      string
+		feedback  models.Feedback
+		mockError error
+		wantError bool
+		invalid   bool
+		errorMsg  string
+	}{
+		{
+			name:     "Успешное создание сообщения",
+			feedback: valid,
+		},
+		{
+			name:      "Пустое имя",
+			feedback:  with(func(f *models.Feedback) { f.AuthorName = "   " }),
+			wantError: true,
+			invalid:   true,
+			errorMsg:  "required fields",
+		},
+		{
+			name:      "Пустое сообщение",
+			feedback:  with(func(f *models.Feedback) { f.Message = "" }),
+			wantError: true,
+			invalid:   true,
+			errorMsg:  "required fields",
+		},
+		{
+			name:      "Слишком длинное сообщение",
+			feedback:  with(func(f *models.Feedback) { f.Message = strings.Repeat("я", MaxFeedbackMessageLength+1) }),
+			wantError: true,
+			invalid:   true,
+			errorMsg:  "message must be at most",
+		},
+		{
+			name:      "Слишком длинный контакт",
+			feedback:  with(func(f *models.Feedback) { f.Contact = strings.Repeat("a", MaxFeedbackContactLength+1) }),
+			wantError: true,
+			invalid:   true,
+			errorMsg:  "contact must be at most",
+		},
+		{
+			name:      "Ошибка репозитория",
+			feedback:  valid,
+			mockError: errors.New("database error"),
+			wantError: true,
+			errorMsg:  "error creating feedback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved models.Feedback
+			mockRepo := &MockFeedbackRepo{
+				CreateFunc: func(f models.Feedback) (models.Feedback, error) {
+					saved = f
+					f.ID = 1
+					return f, tt.mockError
+				},
+			}
+			service := NewFeedbackService(mockRepo)
+
+			result, err := service.Create(tt.feedback)
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("Ожидалась ошибка, но получили nil")
+				}
+				if !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Ожидалось сообщение об ошибке содержащее '%s', получили: %v", tt.errorMsg, err)
+				}
+				if errors.Is(err, ErrInvalidFeedback) != tt.invalid {
+					t.Errorf("errors.Is(err, ErrInvalidFeedback) = %v, ожидалось %v", !tt.invalid, tt.invalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if result.ID != 1 {
+				t.Errorf("Ожидался ID = 1, получили %d", result.ID)
+			}
+			if saved.AuthorName != "Иван" {
+				t.Errorf("Ожидалось имя без пробелов по краям, получили %q", saved.AuthorName)
+			}
+			if saved.Status != FeedbackStatusNew {
+				t.Errorf("Ожидался статус %q, получили %q", FeedbackStatusNew, saved.Status)
+			}
+		})
+	}
+}
+
+// TestFeedbackService_ChangeStatus тестирует переходы между статусами
+func TestFeedbackService_ChangeStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		status    string
+		wantError bool
+		errorMsg  string
+	}{
+		{
+			name:    "Новое -> прочитано",
+			current: FeedbackStatusNew,
+			status:  FeedbackStatusRead,
+		},
+		{
+			name:    "Спам -> новое",
+			current: FeedbackStatusSpam,
+			status:  FeedbackStatusNew,
+		},
+		{
+			name:    "Тот же статус",
+			current: FeedbackStatusRead,
+			status:  FeedbackStatusRead,
+		},
+		{
+			name:      "Отвеченное нельзя вернуть в новые",
+			current:   FeedbackStatusReplied,
+			status:    FeedbackStatusNew,
+			wantError: true,
+			errorMsg:  "replied -> new",
+		},
+		{
+			name:      "Неизвестный статус",
+			current:   FeedbackStatusNew,
+			status:    "deleted",
+			wantError: true,
+			errorMsg:  "unknown status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updateCalls int
+			mockRepo := &MockFeedbackRepo{
+				GetFunc: func(id int64) (models.Feedback, error) {
+					return models.Feedback{ID: id, Status: tt.current}, nil
+				},
+				UpdateStatusFunc: func(ids []int64, status string, from []string) ([]int64, error) {
+					updateCalls++
+					if len(from) != 1 || from[0] != tt.current {
+						t.Errorf("Ожидался from = [%s], получили %v", tt.current, from)
+					}
+					return ids, nil
+				},
+			}
+			service := NewFeedbackService(mockRepo)
+
+			result, err := service.ChangeStatus(1, tt.status)
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("Ожидалась ошибка, но получили nil")
+				}
+				if !errors.Is(err, ErrInvalidStatusTransition) || !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Ожидалась ErrInvalidStatusTransition с '%s', получили: %v", tt.errorMsg, err)
+				}
+				if updateCalls != 0 {
+					t.Errorf("Недопустимый переход не должен доходить до репозитория")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if result.Status != tt.status {
+				t.Errorf("Ожидался статус %q, получили %q", tt.status, result.Status)
+			}
+		})
+	}
+}
+
+// TestFeedbackService_BulkChangeStatus тестирует массовую смену статуса
+func TestFeedbackService_BulkChangeStatus(t *testing.T) {
+	var gotFrom []string
+	mockRepo := &MockFeedbackRepo{
+		UpdateStatusFunc: func(ids []int64, status string, from []string) ([]int64, error) {
+			gotFrom = from
+			return ids[:1], nil
+		},
+	}
+	service := NewFeedbackService(mockRepo)
+
+	updated, err := service.BulkChangeStatus([]int64{1, 2}, FeedbackStatusArchived)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if len(updated) != 1 {
+		t.Errorf("Ожидался 1 обновленный ID, получили %v", updated)
+	}
+	want := []string{FeedbackStatusNew, FeedbackStatusRead, FeedbackStatusReplied, FeedbackStatusSpam}
+	if strings.Join(gotFrom, ",") != strings.Join(want, ",") {
+		t.Errorf("Ожидался from = %v, получили %v", want, gotFrom)
+	}
+
+	if _, err := service.BulkChangeStatus([]int64{1}, "deleted"); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("Ожидалась ErrInvalidStatusTransition, получили: %v", err)
+	}
+}
+
+// TestFeedbackService_Submit тестирует пометку спама вместо отказа
+func TestFeedbackService_Submit(t *testing.T) {
+	flagLinks := SpamCheckerFunc(func(s FeedbackSubmission) (string, bool) {
+		return "links", strings.Contains(s.Feedback.Message, "http")
+	})
+
+	tests := []struct {
+		name       string
+		message    string
+		wantStatus string
+	}{
+		{
+			name:       "Обычное сообщение",
+			message:    "Здравствуйте! Есть вакансия.",
+			wantStatus: FeedbackStatusNew,
+		},
+		{
+			name:       "Спам сохраняется со статусом spam",
+			message:    "Купите http://spam.example",
+			wantStatus: FeedbackStatusSpam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved models.Feedback
+			mockRepo := &MockFeedbackRepo{
+				CreateFunc: func(f models.Feedback) (models.Feedback, error) {
+					saved = f
+					return f, nil
+				},
+			}
+			service := NewFeedbackService(mockRepo, flagLinks)
+
+			_, err := service.Submit(FeedbackSubmission{
+				Feedback: models.Feedback{AuthorName: "Иван", Contact: "ivan@example.com", Message: tt.message},
+			})
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("Ожидался статус %q, получили %q", tt.wantStatus, saved.Status)
+			}
+		})
+	}
+}
+
+// mockFeedbackNotifier запоминает сообщения, о которых пришло уведомление
+type mockFeedbackNotifier struct {
+	notified []models.Feedback
+}
+
+func (m *mockFeedbackNotifier) FeedbackCreated(f models.Feedback) error {
+	m.notified = append(m.notified, f)
+	return nil
+}
+
+// TestFeedbackService_Notify тестирует уведомления о новых сообщениях
+func TestFeedbackService_Notify(t *testing.T) {
+	flagSpam := SpamCheckerFunc(func(s FeedbackSubmission) (string, bool) {
+		return "honeypot", s.Honeypot != ""
+	})
+	notifier := &mockFeedbackNotifier{}
+	service := NewFeedbackService(&MockFeedbackRepo{}, flagSpam)
+	service.SetNotifier(notifier)
+
+	feedback := models.Feedback{AuthorName: "Иван", Contact: "ivan@example.com", Message: "Здравствуйте! Есть вакансия."}
+	if _, err := service.Submit(FeedbackSubmission{Feedback: feedback}); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if _, err := service.Submit(FeedbackSubmission{Feedback: feedback, Honeypot: "x"}); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	if len(notifier.notified) != 1 {
+		t.Errorf("Ожидалось одно уведомление (спам пропускается), получили %d", len(notifier.notified))
+	}
+}
@@ -1,7 +1,299 @@
 package services
 
-type FileService struct{}
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
-func NewFileService() *FileService {
-	return &FileService{}
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
+)
+
+const (
+	// DefaultMaxFileSize максимальный размер загружаемого файла по умолчанию
+	DefaultMaxFileSize = 10 << 20
+	// DefaultFileGCInterval период запуска сборщика неиспользуемых файлов по умолчанию
+	DefaultFileGCInterval = 24 * time.Hour
+	// DefaultFileGCGrace время после загрузки, в течение которого неиспользуемый файл не удаляется
+	DefaultFileGCGrace = 24 * time.Hour
+	// fileSweepBatchSize сколько файлов сборщик обрабатывает за один запрос
+	fileSweepBatchSize = 100
+)
+
+var (
+	// ErrEmptyFile загружен пустой файл
+	ErrEmptyFile = errors.New("file is empty")
+	// ErrFileTooLarge файл больше допустимого размера
+	ErrFileTooLarge = errors.New("file is too large")
+)
+
+// Storage хранилище файлов. Ключ — относительный путь вида "ab/abcdef.png",
+// URL — адрес, по которому файл доступен клиентам.
+type Storage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Delete(key string) error
+	URL(key string) string
+}
+
+// UploadedFile результат загрузки файла
+type UploadedFile struct {
+	Key         string             `json:"key"`
+	Url         string             `json:"url"`
+	Size        int64              `json:"size"`
+	ContentType string             `json:"contentType"`
+	Variants    types.LogoVariants `json:"variants"`
+}
+
+// FileStore интерфейс хранилища метаданных загруженных файлов
+type FileStore interface {
+	Create(models.File) (models.File, error)
+	Orphaned(createdBefore time.Time, limit int) ([]models.File, error)
+	DeleteOrphaned(id int64, createdBefore time.Time) (bool, error)
+}
+
+// FileService сервис загрузки логотипов технологий и мест работы
+type FileService struct {
+	storage Storage
+	files   FileStore
+	maxSize int64
+	now     func() time.Time
+}
+
+// NewFileService создает новый экземпляр сервиса файлов
+func NewFileService(storage Storage, files FileStore, maxSize int64) *FileService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+	return &FileService{
+		storage: storage,
+		files:   files,
+		maxSize: maxSize,
+		now:     time.Now,
+	}
+}
+
+// MaxSize возвращает максимальный размер загружаемого файла
+func (s *FileService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload проверяет изображение по содержимому и сохраняет его в хранилище.
+// SVG очищается от скриптов, для растровых изображений рядом с оригиналом
+// сохраняются уменьшенные копии из ThumbnailSizes. Ключи строятся из SHA-256
+// содержимого, поэтому URL не меняется при повторной загрузке того же файла.
+func (s *FileService) Upload(body io.Reader) (UploadedFile, error) {
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return UploadedFile{}, fmt.Errorf("error reading file: %w", err)
+	}
+	if len(data) == 0 {
+		return UploadedFile{}, ErrEmptyFile
+	}
+	if int64(len(data)) > s.maxSize {
+		return UploadedFile{}, ErrFileTooLarge
+	}
+
+	contentType, err := sniffImageType(data)
+	if err != nil {
+		return UploadedFile{}, err
+	}
+	var img image.Image
+	if contentType == ImageSVG {
+		if data, err = sanitizeSVG(data); err != nil {
+			return UploadedFile{}, err
+		}
+	} else if img, err = decodeImage(data); err != nil {
+		return UploadedFile{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	base := path.Join(hash[:2], hash)
+	key := base + imageExts[contentType]
+
+	if err := s.storage.Put(key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return UploadedFile{}, fmt.Errorf("error storing file: %w", err)
+	}
+	uploaded := UploadedFile{
+		Key:         key,
+		Url:         s.storage.URL(key),
+		Size:        int64(len(data)),
+		ContentType: contentType,
+	}
+	uploaded.Variants.Original = uploaded.Url
+	file := models.File{
+		Key:         key,
+		Url:         uploaded.Url,
+		ContentType: contentType,
+		Size:        uploaded.Size,
+		Keys:        []string{key},
+		Urls:        []string{uploaded.Url},
+	}
+
+	for _, t := range ThumbnailSizes {
+		url := uploaded.Url
+		// SVG масштабируется без потерь, поэтому копии для него не нужны
+		if img != nil {
+			thumb, err := thumbnail(img, t.Size)
+			if err != nil {
+				return UploadedFile{}, err
+			}
+			thumbKey := fmt.Sprintf("%s_%d.png", base, t.Size)
+			if err := s.storage.Put(thumbKey, bytes.NewReader(thumb), int64(len(thumb)), ImagePNG); err != nil {
+				return UploadedFile{}, fmt.Errorf("error storing thumbnail: %w", err)
+			}
+			url = s.storage.URL(thumbKey)
+			file.Keys = append(file.Keys, thumbKey)
+			file.Urls = append(file.Urls, url)
+		}
+		setLogoVariant(&uploaded.Variants, t.Name, url)
+	}
+
+	if _, err := s.files.Create(file); err != nil {
+		return UploadedFile{}, fmt.Errorf("error saving file metadata: %w", err)
+	}
+	return uploaded, nil
+}
+
+// setLogoVariant записывает URL уменьшенной копии в поле варианта по имени размера
+func setLogoVariant(v *types.LogoVariants, name, url string) {
+	switch name {
+	case "sm":
+		v.Sm = url
+	case "md":
+		v.Md = url
+	case "lg":
+		v.Lg = url
+	}
+}
+
+// SweepOrphans удаляет файлы, которые загружены раньше чем grace назад и не используются
+// ни в одном логотипе. При dryRun ничего не удаляется, а возвращаются файлы, которые были бы удалены.
+func (s *FileService) SweepOrphans(grace time.Duration, dryRun bool) ([]models.File, error) {
+	createdBefore := s.now().Add(-grace)
+	var swept []models.File
+	for {
+		orphans, err := s.files.Orphaned(createdBefore, fileSweepBatchSize)
+		if err != nil {
+			return swept, fmt.Errorf("error getting orphaned files: %w", err)
+		}
+		if dryRun {
+			return orphans, nil
+		}
+		before := len(swept)
+		for _, file := range orphans {
+			// Запись удаляется раньше объектов: если файл успели сослать, он останется целым
+			deleted, err := s.files.DeleteOrphaned(file.ID, createdBefore)
+			if err != nil {
+				return swept, fmt.Errorf("error deleting file metadata: %w", err)
+			}
+			if !deleted {
+				continue
+			}
+			for _, key := range file.Keys {
+				if err := s.storage.Delete(key); err != nil {
+					// Объект без записи больше не найдется сборщиком, поэтому только пишем в лог
+					slog.Error("failed to delete orphaned file", "key", key, "error", err)
+				}
+			}
+			swept = append(swept, file)
+		}
+		if len(orphans) < fileSweepBatchSize || len(swept) == before {
+			return swept, nil
+		}
+	}
+}
+
+// RunSweeper периодически удаляет неиспользуемые файлы до отмены ctx
+func (s *FileService) RunSweeper(ctx context.Context, interval, grace time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			swept, err := s.SweepOrphans(grace, false)
+			if err != nil {
+				slog.Error(err.Error())
+			}
+			if len(swept) > 0 {
+				slog.Info("orphaned files deleted", "count", len(swept))
+			}
+		}
+	}
+}
+
+// LocalStorage хранит файлы в каталоге на диске; раздачу файлов по BaseURL
+// обеспечивает роутер
+type LocalStorage struct {
+	Dir     string
+	BaseURL string
+}
+
+// Put реализует Storage
+func (s LocalStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	// Пишем во временный файл и переименовываем, чтобы не отдавать недописанный файл
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file for %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", key, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete реализует Storage. Отсутствующий файл не считается ошибкой.
+func (s LocalStorage) Delete(key string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file %s: %w", key, err)
+	}
+	return nil
+}
+
+// URL реализует Storage
+func (s LocalStorage) URL(key string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+}
+
+// path возвращает путь к файлу на диске, не выпуская ключ за пределы Dir
+func (s LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid file key %q", key)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
 }
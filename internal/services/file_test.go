@@ -0,0 +1,370 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// MockStorage мок-хранилище файлов для тестирования FileService
+type MockStorage struct {
+	objects map[string][]byte
+	types   map[string]string
+	PutErr  error
+}
+
+func newMockStorage() *MockStorage {
+	return &MockStorage{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (m *MockStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	if m.PutErr != nil {
+		return m.PutErr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.objects[key] = data
+	m.types[key] = contentType
+	return nil
+}
+
+func (m *MockStorage) Delete(key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *MockStorage) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+// MockFileStore мок-хранилище метаданных файлов для тестирования FileService
+type MockFileStore struct {
+	files map[int64]models.File
+	// referenced ID файлов, которые используются в логотипах
+	referenced map[int64]bool
+	nextID     int64
+}
+
+func newMockFileStore() *MockFileStore {
+	return &MockFileStore{files: map[int64]models.File{}, referenced: map[int64]bool{}, nextID: 1}
+}
+
+func (m *MockFileStore) Create(file models.File) (models.File, error) {
+	file.ID = m.nextID
+	file.CreatedAt = time.Now()
+	m.nextID++
+	m.files[file.ID] = file
+	return file, nil
+}
+
+func (m *MockFileStore) Orphaned(createdBefore time.Time, limit int) ([]models.File, error) {
+	var res []models.File
+	for id := int64(1); id < m.nextID && len(res) < limit; id++ {
+		file, ok := m.files[id]
+		if ok && !m.referenced[id] && file.CreatedAt.Before(createdBefore) {
+			res = append(res, file)
+		}
+	}
+	return res, nil
+}
+
+func (m *MockFileStore) DeleteOrphaned(id int64, createdBefore time.Time) (bool, error) {
+	file, ok := m.files[id]
+	if !ok || m.referenced[id] || !file.CreatedAt.Before(createdBefore) {
+		return false, nil
+	}
+	delete(m.files, id)
+	return true, nil
+}
+
+// fileKeyPattern ключ оригинала: первые два символа хеша, затем хеш и расширение
+var fileKeyPattern = regexp.MustCompile(`^[0-9a-f]{2}/[0-9a-f]{64}\.[a-z]+$`)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// testPNG создает PNG-изображение заданного размера
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Не удалось закодировать PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestFileService_Upload тестирует метод Upload
+func TestFileService_Upload(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		maxSize     int64
+		putErr      error
+		wantError   error
+		contentType string
+		thumbnails  int
+	}{
+		{
+			name:        "Успешная загрузка PNG с уменьшенными копиями",
+			data:        testPNG(t, 300, 150),
+			contentType: ImagePNG,
+			thumbnails:  3,
+		},
+		{
+			name:        "SVG сохраняется без копий",
+			data:        []byte(`<svg xmlns="http://www.w3.org/2000/svg"><circle r="5"/></svg>`),
+			contentType: ImageSVG,
+		},
+		{
+			name:      "Не изображение",
+			data:      []byte("#!/bin/sh\nrm -rf /"),
+			wantError: ErrUnsupportedFileType,
+		},
+		{
+			name:      "Поврежденный PNG",
+			data:      pngHeader,
+			wantError: ErrInvalidImage,
+		},
+		{
+			name:      "Пустой файл",
+			data:      nil,
+			wantError: ErrEmptyFile,
+		},
+		{
+			name:      "Файл больше лимита",
+			data:      bytes.Repeat([]byte("a"), 11),
+			maxSize:   10,
+			wantError: ErrFileTooLarge,
+		},
+		{
+			name:      "Ошибка хранилища",
+			data:      testPNG(t, 10, 10),
+			putErr:    errors.New("storage unavailable"),
+			wantError: errors.New("error storing file"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := newMockStorage()
+			storage.PutErr = tt.putErr
+			service := NewFileService(storage, newMockFileStore(), tt.maxSize)
+
+			uploaded, err := service.Upload(bytes.NewReader(tt.data))
+
+			if tt.wantError != nil {
+				if err == nil {
+					t.Fatalf("Ожидалась ошибка, но получили nil")
+				}
+				if !errors.Is(err, tt.wantError) && !contains(err.Error(), tt.wantError.Error()) {
+					t.Errorf("Ожидалась ошибка %v, получили: %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if !fileKeyPattern.MatchString(uploaded.Key) {
+				t.Errorf("Неожиданный ключ %q", uploaded.Key)
+			}
+			if uploaded.Url != "https://cdn.example.com/"+uploaded.Key || uploaded.Variants.Original != uploaded.Url {
+				t.Errorf("Неожиданный URL %q", uploaded.Url)
+			}
+			if uploaded.ContentType != tt.contentType || storage.types[uploaded.Key] != tt.contentType {
+				t.Errorf("Ожидался Content-Type %q, получили %q", tt.contentType, uploaded.ContentType)
+			}
+			if len(storage.objects) != 1+tt.thumbnails {
+				t.Errorf("Ожидалось %d объектов в хранилище, получили %d", 1+tt.thumbnails, len(storage.objects))
+			}
+			if uploaded.Variants.Sm == "" || uploaded.Variants.Md == "" || uploaded.Variants.Lg == "" {
+				t.Errorf("Должны быть заполнены все варианты: %+v", uploaded.Variants)
+			}
+			if tt.thumbnails == 0 && uploaded.Variants.Lg != uploaded.Url {
+				t.Errorf("Для SVG варианты должны указывать на оригинал: %+v", uploaded.Variants)
+			}
+		})
+	}
+}
+
+// TestFileService_UploadThumbnails тестирует размеры уменьшенных копий
+func TestFileService_UploadThumbnails(t *testing.T) {
+	storage := newMockStorage()
+	service := NewFileService(storage, newMockFileStore(), 0)
+
+	uploaded, err := service.Upload(bytes.NewReader(testPNG(t, 300, 150)))
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	tests := []struct {
+		url   string
+		wantW int
+		wantH int
+	}{
+		{url: uploaded.Variants.Sm, wantW: 32, wantH: 16},
+		{url: uploaded.Variants.Md, wantW: 64, wantH: 32},
+		// Изображение меньше 256 px не увеличивается
+		{url: uploaded.Variants.Lg, wantW: 256, wantH: 128},
+	}
+	for _, tt := range tests {
+		key := strings.TrimPrefix(tt.url, "https://cdn.example.com/")
+		if storage.types[key] != ImagePNG {
+			t.Errorf("Копия %s должна быть PNG", key)
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(storage.objects[key]))
+		if err != nil {
+			t.Fatalf("Копия %s не декодируется: %v", key, err)
+		}
+		if cfg.Width != tt.wantW || cfg.Height != tt.wantH {
+			t.Errorf("Копия %s: ожидался размер %dx%d, получили %dx%d", key, tt.wantW, tt.wantH, cfg.Width, cfg.Height)
+		}
+	}
+
+	small, err := service.Upload(bytes.NewReader(testPNG(t, 20, 40)))
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	key := strings.TrimPrefix(small.Variants.Lg, "https://cdn.example.com/")
+	cfg, _ := png.DecodeConfig(bytes.NewReader(storage.objects[key]))
+	if cfg.Width != 20 || cfg.Height != 40 {
+		t.Errorf("Маленькое изображение не должно увеличиваться, получили %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+// TestFileService_UploadStableKey тестирует, что одинаковое содержимое получает одинаковый URL
+func TestFileService_UploadStableKey(t *testing.T) {
+	service := NewFileService(newMockStorage(), newMockFileStore(), 0)
+
+	first, err := service.Upload(bytes.NewReader(testPNG(t, 10, 10)))
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	second, err := service.Upload(bytes.NewReader(testPNG(t, 10, 10)))
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	other, err := service.Upload(bytes.NewReader(testPNG(t, 11, 10)))
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+
+	if first.Variants != second.Variants {
+		t.Errorf("Одинаковые файлы должны иметь одни URL: %+v и %+v", first.Variants, second.Variants)
+	}
+	if first.Url == other.Url {
+		t.Errorf("Разные файлы не должны иметь один URL")
+	}
+}
+
+// TestFileService_SweepOrphans тестирует удаление неиспользуемых файлов
+func TestFileService_SweepOrphans(t *testing.T) {
+	storage := newMockStorage()
+	files := newMockFileStore()
+	service := NewFileService(storage, files, 0)
+
+	if _, err := service.Upload(bytes.NewReader(testPNG(t, 10, 10))); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	orphan, err := service.Upload(bytes.NewReader(testPNG(t, 12, 10)))
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	files.referenced[1] = true
+	if len(files.files[2].Keys) != 4 || files.files[2].Keys[0] != orphan.Key {
+		t.Fatalf("В метаданных должны быть ключи оригинала и копий, получили %v", files.files[2].Keys)
+	}
+
+	// В льготный период файлы не удаляются
+	swept, err := service.SweepOrphans(time.Hour, false)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if len(swept) != 0 {
+		t.Errorf("Свежие файлы не должны удаляться, удалено %d", len(swept))
+	}
+
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	swept, err = service.SweepOrphans(time.Hour, true)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if len(swept) != 1 || swept[0].Key != orphan.Key {
+		t.Errorf("Пробный запуск должен вернуть брошенный файл, получили %v", swept)
+	}
+	if len(storage.objects) != 8 || len(files.files) != 2 {
+		t.Errorf("Пробный запуск не должен ничего удалять")
+	}
+
+	swept, err = service.SweepOrphans(time.Hour, false)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if len(swept) != 1 || swept[0].Key != orphan.Key {
+		t.Errorf("Ожидалось удаление брошенного файла, получили %v", swept)
+	}
+	for _, key := range files.files[1].Keys {
+		if _, ok := storage.objects[key]; !ok {
+			t.Errorf("Используемый файл %s не должен удаляться", key)
+		}
+	}
+	if len(storage.objects) != 4 {
+		t.Errorf("Должны быть удалены оригинал и копии брошенного файла, осталось %d объектов", len(storage.objects))
+	}
+	if _, ok := files.files[2]; ok {
+		t.Errorf("Метаданные брошенного файла должны быть удалены")
+	}
+}
+
+// TestLocalStorage тестирует сохранение и удаление файлов на диске
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage := LocalStorage{Dir: dir, BaseURL: "/files/"}
+	key := "files/ab/abcdef.png"
+
+	if err := storage.Put(key, bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "files", "ab", "abcdef.png"))
+	if err != nil {
+		t.Fatalf("Файл не сохранен: %v", err)
+	}
+	if !bytes.Equal(data, pngHeader) {
+		t.Errorf("Содержимое файла не совпадает")
+	}
+	if url := storage.URL(key); url != "/files/files/ab/abcdef.png" {
+		t.Errorf("Неожиданный URL %q", url)
+	}
+
+	if err := storage.Delete(key); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "files", "ab", "abcdef.png")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Файл должен быть удален")
+	}
+	if err := storage.Delete(key); err != nil {
+		t.Errorf("Удаление отсутствующего файла не должно быть ошибкой: %v", err)
+	}
+
+	if err := storage.Put("../escape.png", bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"); err == nil {
+		t.Errorf("Ключ за пределами каталога должен отклоняться")
+	}
+}
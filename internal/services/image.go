@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxImagePixels максимальное число пикселей в загружаемом изображении.
+// Защищает от изображений, которые при декодировании занимают гигабайты памяти.
+const MaxImagePixels = 25_000_000
+
+// Типы изображений, которые принимаются как логотипы
+const (
+	ImagePNG  = "image/png"
+	ImageJPEG = "image/jpeg"
+	ImageWebP = "image/webp"
+	ImageSVG  = "image/svg+xml"
+)
+
+// imageExts расширения файлов для типов изображений
+var imageExts = map[string]string{
+	ImagePNG:  ".png",
+	ImageJPEG: ".jpg",
+	ImageWebP: ".webp",
+	ImageSVG:  ".svg",
+}
+
+// ThumbnailSize размер уменьшенной копии логотипа
+type ThumbnailSize struct {
+	Name string
+	Size int
+}
+
+// ThumbnailSizes уменьшенные копии, создаваемые для растровых логотипов: sm, md, lg
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "sm", Size: 32},
+	{Name: "md", Size: 64},
+	{Name: "lg", Size: 256},
+}
+
+var (
+	// ErrUnsupportedFileType файл не является изображением поддерживаемого формата
+	ErrUnsupportedFileType = errors.New("unsupported file type: only PNG, JPEG, WebP and SVG images are allowed")
+	// ErrInvalidImage файл похож на изображение, но не декодируется
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+// sniffImageType определяет тип изображения по содержимому, а не по имени файла
+func sniffImageType(data []byte) (string, error) {
+	switch http.DetectContentType(data) {
+	case ImagePNG:
+		return ImagePNG, nil
+	case ImageJPEG:
+		return ImageJPEG, nil
+	case ImageWebP:
+		return ImageWebP, nil
+	}
+	if isSVG(data) {
+		return ImageSVG, nil
+	}
+	return "", ErrUnsupportedFileType
+}
+
+// isSVG проверяет, что корневой элемент XML-документа — svg
+func isSVG(data []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			return false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return t.Name.Local == "svg"
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return false
+			}
+		}
+	}
+}
+
+// decodeImage декодирует растровое изображение, предварительно проверив его размеры
+func decodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels exceeds the limit", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	return img, nil
+}
+
+// thumbnail вписывает изображение в квадрат size×size с сохранением пропорций
+// и кодирует его в PNG. Изображения меньше квадрата не увеличиваются.
+func thumbnail(img image.Image, size int) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// svgForbiddenElements элементы, которые удаляются из SVG вместе с содержимым
+var svgForbiddenElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"audio":         true,
+	"video":         true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// sanitizeSVG пересобирает SVG, удаляя скрипты, обработчики событий, внешние ссылки,
+// DOCTYPE, инструкции обработки и комментарии. Логотип из CV может открываться
+// в браузере напрямую, поэтому скрипт в нем выполнился бы в контексте сайта.
+func sanitizeSVG(data []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = true
+
+	var out bytes.Buffer
+	var stack []string
+	skipDepth := 0
+	rootSeen := false
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := xmlName(t.Name)
+			if len(stack) == 0 {
+				if rootSeen || t.Name.Local != "svg" {
+					return nil, fmt.Errorf("%w: root element must be svg", ErrInvalidImage)
+				}
+				rootSeen = true
+			}
+			stack = append(stack, name)
+			if skipDepth > 0 || svgForbiddenElements[strings.ToLower(t.Name.Local)] {
+				skipDepth++
+				continue
+			}
+			out.WriteString("<" + name)
+			for _, attr := range t.Attr {
+				if !svgAttrAllowed(attr) {
+					continue
+				}
+				out.WriteString(" " + xmlName(attr.Name) + `="`)
+				xml.EscapeText(&out, []byte(attr.Value))
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+		case xml.EndElement:
+			name := xmlName(t.Name)
+			if len(stack) == 0 || stack[len(stack)-1] != name {
+				return nil, fmt.Errorf("%w: unexpected closing tag %s", ErrInvalidImage, name)
+			}
+			stack = stack[:len(stack)-1]
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			out.WriteString("</" + name + ">")
+		case xml.CharData:
+			if skipDepth > 0 || len(stack) == 0 {
+				continue
+			}
+			if strings.EqualFold(stack[len(stack)-1], "style") && !svgStyleAllowed(string(t)) {
+				continue
+			}
+			xml.EscapeText(&out, t)
+		}
+		// Комментарии, DOCTYPE и инструкции обработки отбрасываются
+	}
+	if !rootSeen || len(stack) != 0 {
+		return nil, fmt.Errorf("%w: incomplete svg document", ErrInvalidImage)
+	}
+	return out.Bytes(), nil
+}
+
+// svgAttrAllowed проверяет атрибут SVG: запрещены обработчики событий,
+// ссылки не на фрагменты документа и стили с внешними ресурсами
+func svgAttrAllowed(attr xml.Attr) bool {
+	local := strings.ToLower(attr.Name.Local)
+	value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+	switch {
+	case strings.HasPrefix(local, "on"):
+		return false
+	case local == "href" || local == "src":
+		return strings.HasPrefix(value, "#") ||
+			strings.HasPrefix(value, "data:image/png") ||
+			strings.HasPrefix(value, "data:image/jpeg") ||
+			strings.HasPrefix(value, "data:image/webp")
+	case local == "base" && strings.ToLower(attr.Name.Space) == "xml":
+		return false
+	case local == "style":
+		return svgStyleAllowed(value)
+	}
+	return !strings.Contains(value, "javascript:")
+}
+
+// svgStyleAllowed проверяет CSS из атрибута style или элемента <style>
+func svgStyleAllowed(css string) bool {
+	css = strings.ToLower(strings.Join(strings.Fields(css), ""))
+	return !strings.Contains(css, "javascript:") &&
+		!strings.Contains(css, "expression(") &&
+		!strings.Contains(css, "@import") &&
+		!strings.Contains(css, "url(http") &&
+		!strings.Contains(css, "url('http") &&
+		!strings.Contains(css, "url(\"http") &&
+		!strings.Contains(css, "url(//")
+}
+
+// xmlName возвращает имя элемента или атрибута с префиксом пространства имен
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSniffImageType тестирует определение типа изображения по содержимому
+func TestSniffImageType(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		want      string
+		wantError bool
+	}{
+		{name: "PNG", data: pngHeader, want: ImagePNG},
+		{name: "JPEG", data: []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), want: ImageJPEG},
+		{name: "WebP", data: []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), want: ImageWebP},
+		{name: "SVG", data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), want: ImageSVG},
+		{name: "SVG с XML-декларацией и DOCTYPE", data: []byte("<?xml version=\"1.0\"?>\n<!DOCTYPE svg>\n<svg></svg>"), want: ImageSVG},
+		{name: "HTML", data: []byte("<html><body><svg></svg></body></html>"), wantError: true},
+		{name: "GIF", data: []byte("GIF89a\x01\x00\x01\x00"), wantError: true},
+		{name: "Текст", data: []byte("logo.png"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sniffImageType(tt.data)
+			if tt.wantError {
+				if !errors.Is(err, ErrUnsupportedFileType) {
+					t.Errorf("Ожидалась ErrUnsupportedFileType, получили %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Ожидался тип %q, получили %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSanitizeSVG тестирует очистку SVG
+func TestSanitizeSVG(t *testing.T) {
+	tests := []struct {
+		name      string
+		svg       string
+		want      []string
+		notWant   []string
+		wantError bool
+	}{
+		{
+			name:    "Удаление скрипта и обработчиков событий",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><script>alert(2)</script><circle r="5" onclick="alert(3)" fill="red"/></svg>`,
+			want:    []string{`<svg xmlns="http://www.w3.org/2000/svg">`, `<circle r="5" fill="red"></circle>`},
+			notWant: []string{"alert", "script", "onload", "onclick"},
+		},
+		{
+			name:    "Удаление foreignObject вместе с содержимым",
+			svg:     `<svg><foreignObject><iframe src="https://evil.example.com"></iframe></foreignObject><rect/></svg>`,
+			want:    []string{"<rect></rect>"},
+			notWant: []string{"foreignObject", "iframe", "evil"},
+		},
+		{
+			name:    "Внешние и javascript-ссылки удаляются, ссылки на фрагменты остаются",
+			svg:     `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="javascript:alert(1)"><use xlink:href="#icon"/></a><image href="https://evil.example.com/x.png"/></svg>`,
+			want:    []string{`<use xlink:href="#icon">`, `<a>`},
+			notWant: []string{"javascript", "evil"},
+		},
+		{
+			name:    "Стили с внешними ресурсами удаляются",
+			svg:     `<svg><style>@import url(https://evil.example.com/x.css);</style><style>.a { fill: red }</style><rect style="fill: url(http://evil.example.com/#g)"/></svg>`,
+			want:    []string{".a { fill: red }", "<rect></rect>"},
+			notWant: []string{"evil", "@import"},
+		},
+		{
+			name:    "Комментарии и инструкции обработки удаляются",
+			svg:     "<?xml version=\"1.0\"?><?xml-stylesheet href=\"https://evil.example.com/x.css\"?><!-- comment --><svg>text &amp; more</svg>",
+			want:    []string{"<svg>text &amp; more</svg>"},
+			notWant: []string{"evil", "comment", "<?"},
+		},
+		{
+			name:      "Внешние сущности не раскрываются",
+			svg:       `<!DOCTYPE svg [<!ENTITY xxe SYSTEM "file:///etc/passwd">]><svg>&xxe;</svg>`,
+			wantError: true,
+		},
+		{
+			name:      "Корень не svg",
+			svg:       `<html><svg></svg></html>`,
+			wantError: true,
+		},
+		{
+			name:      "Незакрытый документ",
+			svg:       `<svg><g>`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeSVG([]byte(tt.svg))
+			if tt.wantError {
+				if !errors.Is(err, ErrInvalidImage) {
+					t.Errorf("Ожидалась ErrInvalidImage, получили %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+			}
+			for _, s := range tt.want {
+				if !contains(string(got), s) {
+					t.Errorf("Ожидалось %q в результате:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.notWant {
+				if contains(string(got), s) {
+					t.Errorf("Не ожидалось %q в результате:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
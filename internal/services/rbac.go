@@ -0,0 +1,59 @@
+package services
+
+import (
+	"slices"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// Роли пользователей админки. Права ролей хранятся в таблице role_permissions.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+// Roles список известных ролей
+var Roles = []string{RoleViewer, RoleEditor, RoleOwner}
+
+// Права, проверяемые на маршрутах API и страницах админки
+const (
+	PermAdminRead  = "admin:read"
+	PermTagWrite   = "tag:write"
+	PermTagDelete  = "tag:delete"
+	PermTechWrite  = "tech:write"
+	PermTechDelete = "tech:delete"
+	PermWHWrite    = "wh:write"
+	PermWHDelete   = "wh:delete"
+	PermEduWrite   = "edu:write"
+	PermEduDelete  = "edu:delete"
+	PermFbRead     = "fb:read"
+	PermFbWrite    = "fb:write"
+	PermFileWrite  = "file:write"
+	PermUserManage = "user:manage"
+)
+
+// RoleStore интерфейс хранилища ролей и их прав
+type RoleStore interface {
+	Get(name string) (models.Role, error)
+	Permissions(role string) ([]string, error)
+}
+
+// Principal аутентифицированный пользователь вместе с ролью и правами
+type Principal struct {
+	User        models.User
+	Role        models.Role
+	Permissions []string
+	// TokenID ID API-токена, если запрос аутентифицирован токеном, а не сессией
+	TokenID int64
+}
+
+// Can проверяет, есть ли у пользователя право
+func (p Principal) Can(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// IsValidRole проверяет, что роль входит в список известных
+func IsValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
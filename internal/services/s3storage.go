@@ -0,0 +1,182 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3UnsignedPayload значение x-amz-content-sha256 для тела, не входящего в подпись
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Storage хранит файлы в S3-совместимом хранилище (AWS S3, MinIO, Yandex Object Storage).
+// Запросы подписываются AWS Signature Version 4.
+type S3Storage struct {
+	// Endpoint адрес API хранилища, например https://storage.yandexcloud.net
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL адрес, по которому объекты доступны клиентам.
+	// По умолчанию — Endpoint/Bucket.
+	PublicURL string
+	// PathStyle адресует бакет путем (endpoint/bucket/key), а не поддоменом (bucket.endpoint/key).
+	// MinIO обычно требует PathStyle.
+	PathStyle bool
+	Client    *http.Client
+	now       func() time.Time
+}
+
+// Put реализует Storage. Объект загружается с публичным доступом на чтение.
+func (s *S3Storage) Put(key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	req.Header.Set("X-Amz-Acl", "public-read")
+	return s.do(req, key)
+}
+
+// Delete реализует Storage. S3 не возвращает ошибку для отсутствующего объекта.
+func (s *S3Storage) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	return s.do(req, key)
+}
+
+// URL реализует Storage
+func (s *S3Storage) URL(key string) string {
+	if s.PublicURL != "" {
+		return strings.TrimSuffix(s.PublicURL, "/") + "/" + escapeS3Key(key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Storage) do(req *http.Request, key string) error {
+	s.sign(req, s3UnsignedPayload)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s s3 object %s: %w", strings.ToLower(req.Method), key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to %s s3 object %s: %s: %s", strings.ToLower(req.Method), key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// objectURL возвращает адрес объекта в API хранилища
+func (s *S3Storage) objectURL(key string) string {
+	endpoint := strings.TrimSuffix(s.Endpoint, "/")
+	if s.PathStyle {
+		return endpoint + "/" + s.Bucket + "/" + escapeS3Key(key)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint + "/" + s.Bucket + "/" + escapeS3Key(key)
+	}
+	u.Host = s.Bucket + "." + u.Host
+	return u.String() + "/" + escapeS3Key(key)
+}
+
+// sign подписывает запрос по AWS Signature Version 4.
+// В подпись входят Host и все заголовки, уже выставленные в запросе.
+func (s *S3Storage) sign(req *http.Request, payloadHash string) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// canonicalQuery сортирует параметры запроса и кодирует их по правилам SigV4
+func canonicalQuery(values url.Values) string {
+	pairs := make([]string, 0, len(values))
+	for name, vals := range values {
+		for _, v := range vals {
+			pairs = append(pairs, awsEscape(name)+"="+awsEscape(v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// escapeS3Key кодирует ключ объекта, сохраняя разделители "/"
+func escapeS3Key(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = awsEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// awsEscape кодирует строку как URI-компонент по RFC 3986, как того требует SigV4
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
+)
+
+// Ограничения поискового запроса
+const (
+	DefaultSearchLimit   = 20
+	MaxSearchLimit       = 50
+	MaxSearchQueryLength = 200
+)
+
+// ErrInvalidSearchQuery пустой или слишком длинный поисковый запрос
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// Searcher интерфейс полнотекстового поиска по резюме
+type Searcher interface {
+	Search(query string, limit int) ([]types.SearchHit, error)
+}
+
+// SearchService сервис поиска по технологиям, истории работы и образованию
+type SearchService struct {
+	repo Searcher
+}
+
+// NewSearchService создает новый экземпляр сервиса поиска
+func NewSearchService(repo Searcher) *SearchService {
+	return &SearchService{
+		repo: repo,
+	}
+}
+
+// Search ищет записи по запросу. limit вне диапазона 1..MaxSearchLimit
+// заменяется значением по умолчанию или ограничивается.
+func (s *SearchService) Search(query string, limit int) ([]types.SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearchQuery)
+	}
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("%w: query must be at most %d characters", ErrInvalidSearchQuery, MaxSearchQueryLength)
+	}
+	switch {
+	case limit <= 0:
+		limit = DefaultSearchLimit
+	case limit > MaxSearchLimit:
+		limit = MaxSearchLimit
+	}
+
+	hits, err := s.repo.Search(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching: %w", err)
+	}
+	for i := range hits {
+		hits[i].Snippet = escapeSnippet(hits[i].Snippet)
+	}
+	return hits, nil
+}
+
+// escapeSnippet экранирует HTML во фрагменте, оставляя только выделение <mark>,
+// которое добавляет ts_headline
+func escapeSnippet(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, "&lt;mark&gt;", "<mark>")
+	return strings.ReplaceAll(s, "&lt;/mark&gt;", "</mark>")
+}
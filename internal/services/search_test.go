@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
+)
+
+// MockSearchRepo мок-репозиторий для тестирования SearchService
+type MockSearchRepo struct {
+	SearchFunc func(query string, limit int) ([]types.SearchHit, error)
+}
+
+func (m *MockSearchRepo) Search(query string, limit int) ([]types.SearchHit, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(query, limit)
+	}
+	return []types.SearchHit{}, nil
+}
+
+// TestSearchService_Search тестирует метод Search
+func TestSearchService_Search(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		limit     int
+		wantQuery string
+		wantLimit int
+		wantError error
+	}{
+		{
+			name:      "Запрос обрезается, лимит по умолчанию",
+			query:     "  golang  ",
+			wantQuery: "golang",
+			wantLimit: DefaultSearchLimit,
+		},
+		{
+			name:      "Лимит ограничивается максимумом",
+			query:     "go",
+			limit:     1000,
+			wantQuery: "go",
+			wantLimit: MaxSearchLimit,
+		},
+		{
+			name:      "Пустой запрос",
+			query:     "   ",
+			wantError: ErrInvalidSearchQuery,
+		},
+		{
+			name:      "Слишком длинный запрос",
+			query:     strings.Repeat("я", MaxSearchQueryLength+1),
+			wantError: ErrInvalidSearchQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			var gotLimit int
+			service := NewSearchService(&MockSearchRepo{
+				SearchFunc: func(query string, limit int) ([]types.SearchHit, error) {
+					gotQuery, gotLimit = query, limit
+					return []types.SearchHit{}, nil
+				},
+			})
+
+			_, err := service.Search(tt.query, tt.limit)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Search() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search() unexpected error = %v", err)
+			}
+			if gotQuery != tt.wantQuery || gotLimit != tt.wantLimit {
+				t.Errorf("repo.Search(%q, %d), want (%q, %d)", gotQuery, gotLimit, tt.wantQuery, tt.wantLimit)
+			}
+		})
+	}
+}
+
+// TestSearchService_SearchEscapesSnippet проверяет, что во фрагменте остается только выделение <mark>
+func TestSearchService_SearchEscapesSnippet(t *testing.T) {
+	service := NewSearchService(&MockSearchRepo{
+		SearchFunc: func(query string, limit int) ([]types.SearchHit, error) {
+			return []types.SearchHit{{Snippet: `<script>x</script> <mark>Go</mark> & gRPC`}}, nil
+		},
+	})
+
+	hits, err := service.Search("go", 0)
+	if err != nil {
+		t.Fatalf("Search() unexpected error = %v", err)
+	}
+	want := `&lt;script&gt;x&lt;/script&gt; <mark>Go</mark> &amp; gRPC`
+	if hits[0].Snippet != want {
+		t.Errorf("Snippet = %q, want %q", hits[0].Snippet, want)
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// FeedbackSubmission отправка формы обратной связи вместе с данными для проверок на спам
+type FeedbackSubmission struct {
+	Feedback models.Feedback
+	// Honeypot скрытое поле формы, которое заполняют только боты
+	Honeypot string
+	// FormToken токен, выданный при открытии формы, см. FormTokens
+	FormToken string
+}
+
+// SpamChecker проверка отправки на спам. Возвращает причину, если отправка похожа на спам.
+// Спам не отклоняется, а сохраняется со статусом spam.
+type SpamChecker interface {
+	CheckSpam(FeedbackSubmission) (reason string, spam bool)
+}
+
+// SpamCheckerFunc позволяет использовать функцию как SpamChecker
+type SpamCheckerFunc func(FeedbackSubmission) (string, bool)
+
+// CheckSpam вызывает f
+func (f SpamCheckerFunc) CheckSpam(s FeedbackSubmission) (string, bool) {
+	return f(s)
+}
+
+// HoneypotChecker помечает спамом отправки с заполненным скрытым полем
+type HoneypotChecker struct{}
+
+// CheckSpam реализует SpamChecker
+func (HoneypotChecker) CheckSpam(s FeedbackSubmission) (string, bool) {
+	if strings.TrimSpace(s.Honeypot) != "" {
+		return "honeypot field is filled", true
+	}
+	return "", false
+}
+
+// Пороги ContentChecker по умолчанию
+const (
+	DefaultSpamMinMessageLength = 10
+	DefaultSpamMaxLinks         = 2
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+
+// ContentChecker эвристики по тексту сообщения: слишком короткое или со множеством ссылок
+type ContentChecker struct {
+	MinLength int
+	MaxLinks  int
+}
+
+// CheckSpam реализует SpamChecker
+func (c ContentChecker) CheckSpam(s FeedbackSubmission) (string, bool) {
+	message := strings.TrimSpace(s.Feedback.Message)
+	if utf8.RuneCountInString(message) < c.MinLength {
+		return fmt.Sprintf("message is shorter than %d characters", c.MinLength), true
+	}
+	if links := len(linkPattern.FindAllStringIndex(message, -1)); links > c.MaxLinks {
+		return fmt.Sprintf("message contains %d links", links), true
+	}
+	return "", false
+}
+
+// SubmitTimeChecker помечает спамом отправки без действительного токена формы
+// и отправленные быстрее, чем человек успевает заполнить форму
+type SubmitTimeChecker struct {
+	Tokens   *FormTokens
+	MinDelay time.Duration
+}
+
+// CheckSpam реализует SpamChecker
+func (c SubmitTimeChecker) CheckSpam(s FeedbackSubmission) (string, bool) {
+	issuedAt, err := c.Tokens.Verify(s.FormToken)
+	if err != nil {
+		return err.Error(), true
+	}
+	if elapsed := c.Tokens.now().Sub(issuedAt); elapsed < c.MinDelay {
+		return fmt.Sprintf("form submitted in %s", elapsed.Round(time.Millisecond)), true
+	}
+	return "", false
+}
+
+// DefaultFormTokenTTL время жизни токена формы
+const DefaultFormTokenTTL = 24 * time.Hour
+
+// ErrInvalidFormToken токен формы отсутствует, подделан или просрочен
+var ErrInvalidFormToken = errors.New("invalid form token")
+
+// FormTokens выдает и проверяет подписанные токены с временем открытия формы
+type FormTokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewFormTokens создает генератор токенов формы с ключом подписи secret
+func NewFormTokens(secret []byte, ttl time.Duration) *FormTokens {
+	if ttl <= 0 {
+		ttl = DefaultFormTokenTTL
+	}
+	return &FormTokens{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue возвращает токен вида "<время в мс>.<подпись>"
+func (t *FormTokens) Issue() string {
+	ts := strconv.FormatInt(t.now().UnixMilli(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(ts)) + "." + t.sign(ts)
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает время его выдачи
+func (t *FormTokens) Verify(token string) (time.Time, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return time.Time{}, ErrInvalidFormToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return time.Time{}, ErrInvalidFormToken
+	}
+	ts := string(raw)
+	if !hmac.Equal([]byte(signature), []byte(t.sign(ts))) {
+		return time.Time{}, ErrInvalidFormToken
+	}
+	ms, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidFormToken
+	}
+	issuedAt := time.UnixMilli(ms)
+	if t.now().Sub(issuedAt) > t.ttl {
+		return time.Time{}, fmt.Errorf("%w: expired", ErrInvalidFormToken)
+	}
+	return issuedAt, nil
+}
+
+func (t *FormTokens) sign(ts string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(ts))
+	return hex.EncodeToString(mac.Sum(nil))
+}
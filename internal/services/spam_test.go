@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+)
+
+// TestFormTokens тестирует выдачу и проверку токенов формы
+func TestFormTokens(t *testing.T) {
+	now := time.Now()
+	tokens := NewFormTokens([]byte("secret"), time.Hour)
+	tokens.now = func() time.Time { return now }
+
+	token := tokens.Issue()
+	issuedAt, err := tokens.Verify(token)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if issuedAt.UnixMilli() != now.UnixMilli() {
+		t.Errorf("Ожидалось время выдачи %v, получили %v", now, issuedAt)
+	}
+
+	other := NewFormTokens([]byte("other-secret"), time.Hour)
+	if _, err := other.Verify(token); !errors.Is(err, ErrInvalidFormToken) {
+		t.Errorf("Токен с чужой подписью должен быть недействителен, получили: %v", err)
+	}
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	if _, err := tokens.Verify(encoded + "." + strings.Repeat("0", len(signature))); !errors.Is(err, ErrInvalidFormToken) {
+		t.Errorf("Подделанная подпись должна отклоняться, получили: %v", err)
+	}
+	if _, err := tokens.Verify(""); !errors.Is(err, ErrInvalidFormToken) {
+		t.Errorf("Пустой токен должен отклоняться, получили: %v", err)
+	}
+
+	tokens.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrInvalidFormToken) {
+		t.Errorf("Просроченный токен должен отклоняться, получили: %v", err)
+	}
+}
+
+// TestSpamCheckers тестирует встроенные проверки на спам
+func TestSpamCheckers(t *testing.T) {
+	now := time.Now()
+	tokens := NewFormTokens([]byte("secret"), time.Hour)
+	tokens.now = func() time.Time { return now.Add(-time.Minute) }
+	oldToken := tokens.Issue()
+	tokens.now = func() time.Time { return now.Add(-time.Second) }
+	freshToken := tokens.Issue()
+	tokens.now = func() time.Time { return now }
+
+	submission := func(message, honeypot, token string) FeedbackSubmission {
+		return FeedbackSubmission{
+			Feedback:  models.Feedback{Message: message},
+			Honeypot:  honeypot,
+			FormToken: token,
+		}
+	}
+	content := ContentChecker{MinLength: DefaultSpamMinMessageLength, MaxLinks: DefaultSpamMaxLinks}
+	submitTime := SubmitTimeChecker{Tokens: tokens, MinDelay: 3 * time.Second}
+
+	tests := []struct {
+		name       string
+		checker    SpamChecker
+		submission FeedbackSubmission
+		wantSpam   bool
+	}{
+		{
+			name:       "Пустой honeypot",
+			checker:    HoneypotChecker{},
+			submission: submission("Здравствуйте, есть вакансия", "", ""),
+		},
+		{
+			name:       "Заполненный honeypot",
+			checker:    HoneypotChecker{},
+			submission: submission("Здравствуйте, есть вакансия", "http://spam.example", ""),
+			wantSpam:   true,
+		},
+		{
+			name:       "Обычное сообщение со ссылкой",
+			checker:    content,
+			submission: submission("Вакансия: https://hh.ru/vacancy/1", "", ""),
+		},
+		{
+			name:       "Слишком короткое сообщение",
+			checker:    content,
+			submission: submission("hi", "", ""),
+			wantSpam:   true,
+		},
+		{
+			name:       "Много ссылок",
+			checker:    content,
+			submission: submission("buy http://a.example www.b.example https://c.example", "", ""),
+			wantSpam:   true,
+		},
+		{
+			name:       "Форма заполнялась минуту",
+			checker:    submitTime,
+			submission: submission("Здравствуйте, есть вакансия", "", oldToken),
+		},
+		{
+			name:       "Форма отправлена через секунду",
+			checker:    submitTime,
+			submission: submission("Здравствуйте, есть вакансия", "", freshToken),
+			wantSpam:   true,
+		},
+		{
+			name:       "Без токена формы",
+			checker:    submitTime,
+			submission: submission("Здравствуйте, есть вакансия", "", ""),
+			wantSpam:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, spam := tt.checker.CheckSpam(tt.submission)
+			if spam != tt.wantSpam {
+				t.Errorf("Ожидалось spam = %v, получили %v (%s)", tt.wantSpam, spam, reason)
+			}
+			if spam && reason == "" {
+				t.Errorf("Для спама ожидалась причина")
+			}
+		})
+	}
+}
@@ -19,11 +19,18 @@ type TagWriter interface {
 type TagReader interface {
 	Get(id int64) (models.Tag, error)
 	List(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Tag], error)
+	Schema() entityreqdecorator.Schema
+}
+
+// TagTechReader интерфейс для получения технологий с тегом
+type TagTechReader interface {
+	Technologies(tagID int64) ([]models.Technology, error)
 }
 type TagManager interface {
 	TagReader
 	TagWriter
 	TagDeleter
+	TagTechReader
 }
 type TagService struct {
 	repo TagManager
@@ -57,6 +64,11 @@ func (s *TagService) Delete(id int64) (int64, error) {
 	return res, nil
 }
 
+// Schema возвращает схему полей тегов для разбора параметра fields
+func (s *TagService) Schema() entityreqdecorator.Schema {
+	return s.repo.Schema()
+}
+
 // Get получает один тег по ID
 func (s *TagService) Get(id int64) (models.Tag, error) {
 	if id == 0 {
@@ -69,6 +81,18 @@ func (s *TagService) Get(id int64) (models.Tag, error) {
 	return res, nil
 }
 
+// Technologies получает технологии с тегом
+func (s *TagService) Technologies(tagID int64) ([]models.Technology, error) {
+	if tagID == 0 {
+		return nil, fmt.Errorf("invalid tag ID: %d", tagID)
+	}
+	res, err := s.repo.Technologies(tagID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag technologies: %w", err)
+	}
+	return res, nil
+}
+
 // List получает список тегов с пагинацией и фильтрацией
 func (s *TagService) List(r entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Tag], error) {
 	res, err := s.repo.List(r)
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
@@ -10,12 +11,17 @@ import (
 
 // MockTagRepo мок-репозиторий для тестирования TagService
 type MockTagRepo struct {
-	GetFunc        func(id int64) (models.Tag, error)
-	ListFunc       func(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Tag], error)
-	CreateFunc     func(models.Tag) (models.Tag, error)
-	UpdateFunc     func(models.Tag) (models.Tag, error)
-	DeleteFunc     func(id int64) (int64, error)
-	DeleteListFunc func([]int64) ([]int64, error)
+	GetFunc          func(id int64) (models.Tag, error)
+	ListFunc         func(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Tag], error)
+	CreateFunc       func(models.Tag) (models.Tag, error)
+	UpdateFunc       func(models.Tag) (models.Tag, error)
+	DeleteFunc       func(id int64) (int64, error)
+	DeleteListFunc   func([]int64) ([]int64, error)
+	TechnologiesFunc func(tagID int64) ([]models.Technology, error)
+}
+
+func (m *MockTagRepo) Schema() entityreqdecorator.Schema {
+	return nil
 }
 
 func (m *MockTagRepo) Get(id int64) (models.Tag, error) {
@@ -60,6 +66,13 @@ func (m *MockTagRepo) DeleteList(ids []int64) ([]int64, error) {
 	return nil, nil
 }
 
+func (m *MockTagRepo) Technologies(tagID int64) ([]models.Technology, error) {
+	if m.TechnologiesFunc != nil {
+		return m.TechnologiesFunc(tagID)
+	}
+	return []models.Technology{}, nil
+}
+
 // TestTagService_Get тестирует метод Get
 func TestTagService_Get(t *testing.T) {
 	tests := []struct {
@@ -530,3 +543,27 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// TestTagService_Technologies тестирует метод Technologies
+func TestTagService_Technologies(t *testing.T) {
+	mockRepo := &MockTagRepo{
+		TechnologiesFunc: func(tagID int64) ([]models.Technology, error) {
+			if tagID == 404 {
+				return nil, fmt.Errorf("tag with id %d %w", tagID, entityreqdecorator.ErrNotFound)
+			}
+			return []models.Technology{{ID: 1, Title: "Go"}}, nil
+		},
+	}
+	service := NewTagServise(mockRepo)
+
+	result, err := service.Technologies(1)
+	if err != nil || len(result) != 1 {
+		t.Errorf("Technologies(1) = %v, %v", result, err)
+	}
+	if _, err := service.Technologies(0); err == nil {
+		t.Errorf("Ожидалась ошибка для ID 0")
+	}
+	if _, err := service.Technologies(404); !errors.Is(err, entityreqdecorator.ErrNotFound) {
+		t.Errorf("Ожидалась ErrNotFound, получили: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"slices"
 
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
@@ -19,11 +20,25 @@ type TechWriter interface {
 type TechReader interface {
 	Get(id int64) (models.Technology, error)
 	List(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Technology], error)
+	Schema() entityreqdecorator.Schema
+}
+
+// TechTagsLoader интерфейс для подгрузки тегов нескольких технологий одним запросом
+type TechTagsLoader interface {
+	TagsByTechnology(techIDs []int64) (map[int64][]models.Tag, error)
+}
+
+// TechTagManager интерфейс для работы с тегами технологии
+type TechTagManager interface {
+	Tags(techID int64) ([]models.Tag, error)
+	SetTags(techID int64, tagIDs []int64) ([]models.Tag, error)
+	TechTagsLoader
 }
 type TechManager interface {
 	TechReader
 	TechWriter
 	TechDeleter
+	TechTagManager
 }
 type TechService struct {
 	repo TechManager
@@ -56,6 +71,11 @@ func (s *TechService) Delete(id int64) (int64, error) {
 	return res, nil
 }
 
+// Schema возвращает схему полей технологий для разбора параметра fields
+func (s *TechService) Schema() entityreqdecorator.Schema {
+	return s.repo.Schema()
+}
+
 // Get получает одну технологию по ID
 func (s *TechService) Get(id int64) (models.Technology, error) {
 	if id == 0 {
@@ -105,3 +125,73 @@ func (s *TechService) Update(technology models.Technology) (models.Technology, e
 	}
 	return res, nil
 }
+
+// Tags получает теги технологии
+func (s *TechService) Tags(techID int64) ([]models.Tag, error) {
+	if techID == 0 {
+		return nil, fmt.Errorf("invalid technology ID: %d", techID)
+	}
+	res, err := s.repo.Tags(techID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting technology tags: %w", err)
+	}
+	return res, nil
+}
+
+// SetTags заменяет теги технологии на tagIDs и возвращает новый список тегов
+func (s *TechService) SetTags(techID int64, tagIDs []int64) ([]models.Tag, error) {
+	if techID == 0 {
+		return nil, fmt.Errorf("invalid technology ID: %d", techID)
+	}
+	res, err := s.repo.SetTags(techID, tagIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error setting technology tags: %w", err)
+	}
+	return res, nil
+}
+
+// TagsByTechnology получает теги нескольких технологий одним запросом
+func (s *TechService) TagsByTechnology(techIDs []int64) (map[int64][]models.Tag, error) {
+	res, err := s.repo.TagsByTechnology(techIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting technology tags: %w", err)
+	}
+	return res, nil
+}
+
+// TechIncludes связи, которые можно подгрузить к технологиям параметром include
+var TechIncludes = []string{"tags"}
+
+// Expand подгружает к технологиям связи из include
+func (s *TechService) Expand(technologies []models.Technology, include []string) ([]entityreqdecorator.Expanded[models.Technology], error) {
+	expanded := entityreqdecorator.Expand(technologies)
+	if slices.Contains(include, "tags") {
+		if err := withTags(s.repo, expanded); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// withTags добавляет к технологиям теги, подгруженные одним запросом
+func withTags(loader TechTagsLoader, technologies []entityreqdecorator.Expanded[models.Technology]) error {
+	if len(technologies) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(technologies))
+	for i, t := range technologies {
+		ids[i] = t.Item.ID
+	}
+	tags, err := loader.TagsByTechnology(ids)
+	if err != nil {
+		return fmt.Errorf("error getting technology tags: %w", err)
+	}
+	for _, t := range technologies {
+		techTags := tags[t.Item.ID]
+		if techTags == nil {
+			techTags = []models.Tag{}
+		}
+		t.Relations["tags"] = techTags
+	}
+	return nil
+}
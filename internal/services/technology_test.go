@@ -1,7 +1,9 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -12,12 +14,19 @@ import (
 
 // MockTechRepo мок-репозиторий для тестирования TechService
 type MockTechRepo struct {
-	GetFunc        func(id int64) (models.Technology, error)
-	ListFunc       func(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Technology], error)
-	CreateFunc     func(models.Technology) (models.Technology, error)
-	UpdateFunc     func(models.Technology) (models.Technology, error)
-	DeleteFunc     func(id int64) (int64, error)
-	DeleteListFunc func([]int64) ([]int64, error)
+	GetFunc              func(id int64) (models.Technology, error)
+	ListFunc             func(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.Technology], error)
+	CreateFunc           func(models.Technology) (models.Technology, error)
+	UpdateFunc           func(models.Technology) (models.Technology, error)
+	DeleteFunc           func(id int64) (int64, error)
+	DeleteListFunc       func([]int64) ([]int64, error)
+	TagsFunc             func(techID int64) ([]models.Tag, error)
+	SetTagsFunc          func(techID int64, tagIDs []int64) ([]models.Tag, error)
+	TagsByTechnologyFunc func(techIDs []int64) (map[int64][]models.Tag, error)
+}
+
+func (m *MockTechRepo) Schema() entityreqdecorator.Schema {
+	return nil
 }
 
 func (m *MockTechRepo) Get(id int64) (models.Technology, error) {
@@ -62,6 +71,27 @@ func (m *MockTechRepo) DeleteList(ids []int64) ([]int64, error) {
 	return nil, nil
 }
 
+func (m *MockTechRepo) Tags(techID int64) ([]models.Tag, error) {
+	if m.TagsFunc != nil {
+		return m.TagsFunc(techID)
+	}
+	return []models.Tag{}, nil
+}
+
+func (m *MockTechRepo) SetTags(techID int64, tagIDs []int64) ([]models.Tag, error) {
+	if m.SetTagsFunc != nil {
+		return m.SetTagsFunc(techID, tagIDs)
+	}
+	return []models.Tag{}, nil
+}
+
+func (m *MockTechRepo) TagsByTechnology(techIDs []int64) (map[int64][]models.Tag, error) {
+	if m.TagsByTechnologyFunc != nil {
+		return m.TagsByTechnologyFunc(techIDs)
+	}
+	return map[int64][]models.Tag{}, nil
+}
+
 // TestTechService_Get тестирует метод Get
 func TestTechService_Get(t *testing.T) {
 	tests := []struct {
@@ -509,3 +539,104 @@ func TestTechService_DeleteList(t *testing.T) {
 		})
 	}
 }
+
+// TestTechService_SetTags тестирует метод SetTags
+func TestTechService_SetTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		techID    int64
+		tagIDs    []int64
+		mockError error
+		wantError error
+	}{
+		{
+			name:   "Успешная замена тегов",
+			techID: 1,
+			tagIDs: []int64{2, 3},
+		},
+		{
+			name:   "Очистка тегов",
+			techID: 1,
+			tagIDs: []int64{},
+		},
+		{
+			name:      "Технология не найдена",
+			techID:    404,
+			tagIDs:    []int64{2},
+			mockError: fmt.Errorf("technology with id 404 %w", entityreqdecorator.ErrNotFound),
+			wantError: entityreqdecorator.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []int64
+			mockRepo := &MockTechRepo{
+				SetTagsFunc: func(techID int64, tagIDs []int64) ([]models.Tag, error) {
+					gotIDs = tagIDs
+					return []models.Tag{}, tt.mockError
+				},
+			}
+			service := NewTechService(mockRepo)
+
+			_, err := service.SetTags(tt.techID, tt.tagIDs)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Ожидалась ошибка %v, получили: %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Не ожидалась ошибка, получили: %v", err)
+			}
+			if len(gotIDs) != len(tt.tagIDs) {
+				t.Errorf("В репозиторий переданы теги %v, ожидались %v", gotIDs, tt.tagIDs)
+			}
+		})
+	}
+
+	if _, err := NewTechService(&MockTechRepo{}).SetTags(0, []int64{1}); err == nil {
+		t.Errorf("Ожидалась ошибка для ID 0")
+	}
+}
+
+// TestTechService_Expand тестирует подгрузку тегов через include
+func TestTechService_Expand(t *testing.T) {
+	calls := 0
+	mockRepo := &MockTechRepo{
+		TagsByTechnologyFunc: func(techIDs []int64) (map[int64][]models.Tag, error) {
+			calls++
+			return map[int64][]models.Tag{1: {{ID: 10, Name: "Backend"}}}, nil
+		},
+	}
+	service := NewTechService(mockRepo)
+	technologies := []models.Technology{{ID: 1, Title: "Go"}, {ID: 2, Title: "Rust"}}
+
+	// Без include ответ не меняется
+	expanded, err := service.Expand(technologies, nil)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	data, _ := json.Marshal(expanded)
+	if want := `[{"id":1,"title":"Go","description":null,"logoUrl":null},{"id":2,"title":"Rust","description":null,"logoUrl":null}]`; string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+	if calls != 0 {
+		t.Errorf("Без include теги не должны загружаться")
+	}
+
+	expanded, err = service.Expand(technologies, []string{"tags"})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Теги должны загружаться одним запросом, запросов: %d", calls)
+	}
+	data, _ = json.Marshal(expanded[1])
+	if want := `{"description":null,"id":2,"logoUrl":null,"tags":[],"title":"Rust"}`; string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+	if tags := expanded[0].Relations["tags"].([]models.Tag); len(tags) != 1 || tags[0].ID != 10 {
+		t.Errorf("Ожидался тег 10, получили %v", tags)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"slices"
 
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
@@ -15,14 +16,23 @@ type WorkHistoryDeleter interface {
 
 // WorkHistoryWriter интерфейс для создания и обновления записей истории работы
 type WorkHistoryWriter interface {
-	Create(models.WorkHistory) (models.WorkHistory, error)
-	Update(models.WorkHistory) (models.WorkHistory, error)
+	Create(models.WorkHistory, []int64) (models.WorkHistory, error)
+	Update(models.WorkHistory, []int64) (models.WorkHistory, error)
 }
 
 // WorkHistoryReader интерфейс для чтения записей истории работы
 type WorkHistoryReader interface {
 	Get(id int64) (models.WorkHistory, error)
 	List(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.WorkHistory], error)
+	Schema() entityreqdecorator.Schema
+}
+
+// WorkHistoryTechManager интерфейс для работы с технологиями места работы
+type WorkHistoryTechManager interface {
+	Technologies(whID int64) ([]models.Technology, error)
+	SetTechnologies(whID int64, technologyIDs []int64) ([]models.Technology, error)
+	TechnologiesByWorkHistory(whIDs []int64) (map[int64][]models.Technology, error)
+	TechTagsLoader
 }
 
 // WorkHistoryManager объединяет все интерфейсы для работы с историей работы
@@ -30,6 +40,13 @@ type WorkHistoryManager interface {
 	WorkHistoryReader
 	WorkHistoryWriter
 	WorkHistoryDeleter
+	WorkHistoryTechManager
+}
+
+// WorkHistoryWithTechnologies запись истории работы вместе с использованными технологиями
+type WorkHistoryWithTechnologies struct {
+	models.WorkHistory
+	Technologies []models.Technology `json:"technologies"`
 }
 
 // WorkHistoryService сервис для работы с историей работы
@@ -68,16 +85,21 @@ func (s *WorkHistoryService) Delete(id int64) (int64, error) {
 	return res, nil
 }
 
-// Get получает одну запись истории работы по ID
-func (s *WorkHistoryService) Get(id int64) (models.WorkHistory, error) {
+// Schema возвращает схему полей истории работы для разбора параметра fields
+func (s *WorkHistoryService) Schema() entityreqdecorator.Schema {
+	return s.repo.Schema()
+}
+
+// Get получает одну запись истории работы по ID вместе с технологиями
+func (s *WorkHistoryService) Get(id int64) (WorkHistoryWithTechnologies, error) {
 	if id == 0 {
-		return models.WorkHistory{}, fmt.Errorf("invalid work history ID: %d", id)
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("invalid work history ID: %d", id)
 	}
 	res, err := s.repo.Get(id)
 	if err != nil {
-		return models.WorkHistory{}, fmt.Errorf("error getting work history: %w", err)
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("error getting work history: %w", err)
 	}
-	return res, nil
+	return s.withTechnologies(res)
 }
 
 // List получает список записей истории работы с пагинацией и фильтрацией
@@ -89,29 +111,139 @@ func (s *WorkHistoryService) List(r entityreqdecorator.PagebleRq) (entityreqdeco
 	return res, nil
 }
 
-// Create создает новую запись истории работы
-func (s *WorkHistoryService) Create(workHistory models.WorkHistory) (models.WorkHistory, error) {
+// Create создает новую запись истории работы с технологиями technologyIDs
+func (s *WorkHistoryService) Create(workHistory models.WorkHistory, technologyIDs []int64) (WorkHistoryWithTechnologies, error) {
 	if workHistory.Name == "" || workHistory.About == "" {
-		return models.WorkHistory{}, fmt.Errorf("name and about are required fields")
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("name and about are required fields")
+	}
+	if workHistory.LogoUrl != nil && workHistory.LogoUrl.Original == "" {
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("logoUrl.original is required when logoUrl is set")
 	}
-	res, err := s.repo.Create(workHistory)
+	res, err := s.repo.Create(workHistory, technologyIDs)
 	if err != nil {
-		return models.WorkHistory{}, fmt.Errorf("error creating work history: %w", err)
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("error creating work history: %w", err)
 	}
-	return res, nil
+	return s.withTechnologies(res)
 }
 
-// Update обновляет существующую запись истории работы
-func (s *WorkHistoryService) Update(workHistory models.WorkHistory) (models.WorkHistory, error) {
+// Update обновляет существующую запись истории работы. Технологии заменяются
+// на technologyIDs; при technologyIDs == nil остаются прежними.
+func (s *WorkHistoryService) Update(workHistory models.WorkHistory, technologyIDs []int64) (WorkHistoryWithTechnologies, error) {
 	if workHistory.ID == 0 {
-		return models.WorkHistory{}, fmt.Errorf("invalid work history ID: %d", workHistory.ID)
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("invalid work history ID: %d", workHistory.ID)
 	}
 	if workHistory.Name == "" || workHistory.About == "" {
-		return models.WorkHistory{}, fmt.Errorf("name and about are required fields")
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("name and about are required fields")
+	}
+	if workHistory.LogoUrl != nil && workHistory.LogoUrl.Original == "" {
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("logoUrl.original is required when logoUrl is set")
+	}
+	res, err := s.repo.Update(workHistory, technologyIDs)
+	if err != nil {
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("error updating work history: %w", err)
 	}
-	res, err := s.repo.Update(workHistory)
+	return s.withTechnologies(res)
+}
+
+// Technologies получает технологии места работы
+func (s *WorkHistoryService) Technologies(whID int64) ([]models.Technology, error) {
+	if whID == 0 {
+		return nil, fmt.Errorf("invalid work history ID: %d", whID)
+	}
+	res, err := s.repo.Technologies(whID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting work history technologies: %w", err)
+	}
+	return res, nil
+}
+
+// SetTechnologies заменяет технологии места работы на technologyIDs
+func (s *WorkHistoryService) SetTechnologies(whID int64, technologyIDs []int64) ([]models.Technology, error) {
+	if whID == 0 {
+		return nil, fmt.Errorf("invalid work history ID: %d", whID)
+	}
+	res, err := s.repo.SetTechnologies(whID, technologyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error setting work history technologies: %w", err)
+	}
+	return res, nil
+}
+
+// TechnologiesByWorkHistory получает технологии нескольких записей истории работы одним запросом
+func (s *WorkHistoryService) TechnologiesByWorkHistory(whIDs []int64) (map[int64][]models.Technology, error) {
+	res, err := s.repo.TechnologiesByWorkHistory(whIDs)
 	if err != nil {
-		return models.WorkHistory{}, fmt.Errorf("error updating work history: %w", err)
+		return nil, fmt.Errorf("error getting work history technologies: %w", err)
 	}
 	return res, nil
 }
+
+// withTechnologies дополняет запись истории работы ее технологиями
+func (s *WorkHistoryService) withTechnologies(workHistory models.WorkHistory) (WorkHistoryWithTechnologies, error) {
+	technologies, err := s.repo.Technologies(workHistory.ID)
+	if err != nil {
+		return WorkHistoryWithTechnologies{}, fmt.Errorf("error getting work history technologies: %w", err)
+	}
+	return WorkHistoryWithTechnologies{WorkHistory: workHistory, Technologies: technologies}, nil
+}
+
+// WorkHistoryIncludes связи, которые можно подгрузить к истории работы параметром include
+var WorkHistoryIncludes = []string{"technologies", "technologies.tags"}
+
+// Expand подгружает к записям истории работы связи из include: по одному запросу
+// на технологии всех записей и на теги всех технологий
+func (s *WorkHistoryService) Expand(items []models.WorkHistory, include []string) ([]entityreqdecorator.Expanded[models.WorkHistory], error) {
+	if !slices.Contains(include, "technologies") || len(items) == 0 {
+		return entityreqdecorator.Expand(items), nil
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	technologies, err := s.repo.TechnologiesByWorkHistory(ids)
+	if err != nil {
+		return nil, fmt.Errorf("error getting work history technologies: %w", err)
+	}
+	return s.expand(items, technologies, include)
+}
+
+// ExpandOne подгружает связи из include к записи, полученной через Get.
+// Технологии Get уже загрузил, поэтому запрашиваются только их теги.
+func (s *WorkHistoryService) ExpandOne(item WorkHistoryWithTechnologies, include []string) (entityreqdecorator.Expanded[models.WorkHistory], error) {
+	technologies := map[int64][]models.Technology{item.ID: item.Technologies}
+	expanded, err := s.expand([]models.WorkHistory{item.WorkHistory}, technologies, include)
+	if err != nil {
+		return entityreqdecorator.Expanded[models.WorkHistory]{}, err
+	}
+	return expanded[0], nil
+}
+
+// expand раскладывает загруженные технологии по записям и подгружает их теги для technologies.tags
+func (s *WorkHistoryService) expand(items []models.WorkHistory, technologies map[int64][]models.Technology, include []string) ([]entityreqdecorator.Expanded[models.WorkHistory], error) {
+	expanded := entityreqdecorator.Expand(items)
+	if !slices.Contains(include, "technologies") {
+		return expanded, nil
+	}
+
+	// Relations общий у копий Expanded, поэтому теги, добавленные в all, видны в записях
+	withTechTags := slices.Contains(include, "technologies.tags")
+	var all []entityreqdecorator.Expanded[models.Technology]
+	for _, e := range expanded {
+		whTechnologies := technologies[e.Item.ID]
+		if whTechnologies == nil {
+			whTechnologies = []models.Technology{}
+		}
+		if !withTechTags {
+			e.Relations["technologies"] = whTechnologies
+			continue
+		}
+		expandedTechnologies := entityreqdecorator.Expand(whTechnologies)
+		all = append(all, expandedTechnologies...)
+		e.Relations["technologies"] = expandedTechnologies
+	}
+	if err := withTags(s.repo, all); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
@@ -2,12 +2,14 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
 	models "github.com/Maxim-Ba/cv-backend/internal/models/gen"
+	"github.com/Maxim-Ba/cv-backend/internal/models/types"
 	entityreqdecorator "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 )
 
@@ -15,10 +17,19 @@ import (
 type MockWorkHistoryRepo struct {
 	GetFunc        func(id int64) (models.WorkHistory, error)
 	ListFunc       func(entityreqdecorator.PagebleRq) (entityreqdecorator.PagebleRs[models.WorkHistory], error)
-	CreateFunc     func(models.WorkHistory) (models.WorkHistory, error)
-	UpdateFunc     func(models.WorkHistory) (models.WorkHistory, error)
+	CreateFunc     func(models.WorkHistory, []int64) (models.WorkHistory, error)
+	UpdateFunc     func(models.WorkHistory, []int64) (models.WorkHistory, error)
 	DeleteFunc     func(id int64) (int64, error)
 	DeleteListFunc func([]int64) ([]int64, error)
+
+	TechnologiesFunc              func(whID int64) ([]models.Technology, error)
+	SetTechnologiesFunc           func(whID int64, technologyIDs []int64) ([]models.Technology, error)
+	TechnologiesByWorkHistoryFunc func(whIDs []int64) (map[int64][]models.Technology, error)
+	TagsByTechnologyFunc          func(techIDs []int64) (map[int64][]models.Tag, error)
+}
+
+func (m *MockWorkHistoryRepo) Schema() entityreqdecorator.Schema {
+	return nil
 }
 
 func (m *MockWorkHistoryRepo) Get(id int64) (models.WorkHistory, error) {
@@ -35,16 +46,16 @@ func (m *MockWorkHistoryRepo) List(req entityreqdecorator.PagebleRq) (entityreqd
 	return entityreqdecorator.PagebleRs[models.WorkHistory]{}, nil
 }
 
-func (m *MockWorkHistoryRepo) Create(wh models.WorkHistory) (models.WorkHistory, error) {
+func (m *MockWorkHistoryRepo) Create(wh models.WorkHistory, technologyIDs []int64) (models.WorkHistory, error) {
 	if m.CreateFunc != nil {
-		return m.CreateFunc(wh)
+		return m.CreateFunc(wh, technologyIDs)
 	}
 	return models.WorkHistory{}, nil
 }
 
-func (m *MockWorkHistoryRepo) Update(wh models.WorkHistory) (models.WorkHistory, error) {
+func (m *MockWorkHistoryRepo) Update(wh models.WorkHistory, technologyIDs []int64) (models.WorkHistory, error) {
 	if m.UpdateFunc != nil {
-		return m.UpdateFunc(wh)
+		return m.UpdateFunc(wh, technologyIDs)
 	}
 	return models.WorkHistory{}, nil
 }
@@ -63,6 +74,34 @@ func (m *MockWorkHistoryRepo) DeleteList(ids []int64) ([]int64, error) {
 	return nil, nil
 }
 
+func (m *MockWorkHistoryRepo) Technologies(whID int64) ([]models.Technology, error) {
+	if m.TechnologiesFunc != nil {
+		return m.TechnologiesFunc(whID)
+	}
+	return []models.Technology{}, nil
+}
+
+func (m *MockWorkHistoryRepo) SetTechnologies(whID int64, technologyIDs []int64) ([]models.Technology, error) {
+	if m.SetTechnologiesFunc != nil {
+		return m.SetTechnologiesFunc(whID, technologyIDs)
+	}
+	return []models.Technology{}, nil
+}
+
+func (m *MockWorkHistoryRepo) TechnologiesByWorkHistory(whIDs []int64) (map[int64][]models.Technology, error) {
+	if m.TechnologiesByWorkHistoryFunc != nil {
+		return m.TechnologiesByWorkHistoryFunc(whIDs)
+	}
+	return map[int64][]models.Technology{}, nil
+}
+
+func (m *MockWorkHistoryRepo) TagsByTechnology(techIDs []int64) (map[int64][]models.Tag, error) {
+	if m.TagsByTechnologyFunc != nil {
+		return m.TagsByTechnologyFunc(techIDs)
+	}
+	return map[int64][]models.Tag{}, nil
+}
+
 // TestWorkHistoryService_Get тестирует метод Get
 func TestWorkHistoryService_Get(t *testing.T) {
 	testDate := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -82,7 +121,7 @@ func TestWorkHistoryService_Get(t *testing.T) {
 				ID:          1,
 				Name:        "Яндекс",
 				About:       "Работал Backend разработчиком",
-				LogoUrl:     []byte("logo.png"),
+				LogoUrl:     &types.LogoVariants{Original: "logo.png"},
 				PeriodStart: pgtype.Date{Time: testDate, Valid: true},
 				PeriodEnd:   pgtype.Date{Time: testDate.AddDate(2, 0, 0), Valid: true},
 				WhatIDid:    []string{"Разработка API", "Оптимизация БД"},
@@ -254,7 +293,7 @@ func TestWorkHistoryService_Create(t *testing.T) {
 			wh: models.WorkHistory{
 				Name:        "Тинькофф",
 				About:       "Go разработчик",
-				LogoUrl:     []byte("tinkoff.png"),
+				LogoUrl:     &types.LogoVariants{Original: "tinkoff.png", Sm: "tinkoff_32.png"},
 				PeriodStart: pgtype.Date{Time: testDate, Valid: true},
 				PeriodEnd:   pgtype.Date{Time: testDate.AddDate(1, 0, 0), Valid: true},
 				WhatIDid:    []string{"Микросервисы", "Kafka"},
@@ -264,7 +303,7 @@ func TestWorkHistoryService_Create(t *testing.T) {
 				ID:          3,
 				Name:        "Тинькофф",
 				About:       "Go разработчик",
-				LogoUrl:     []byte("tinkoff.png"),
+				LogoUrl:     &types.LogoVariants{Original: "tinkoff.png", Sm: "tinkoff_32.png"},
 				PeriodStart: pgtype.Date{Time: testDate, Valid: true},
 				PeriodEnd:   pgtype.Date{Time: testDate.AddDate(1, 0, 0), Valid: true},
 				WhatIDid:    []string{"Микросервисы", "Kafka"},
@@ -307,14 +346,14 @@ func TestWorkHistoryService_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
 			mockRepo := &MockWorkHistoryRepo{
-				CreateFunc: func(wh models.WorkHistory) (models.WorkHistory, error) {
+				CreateFunc: func(wh models.WorkHistory, technologyIDs []int64) (models.WorkHistory, error) {
 					return tt.mockWH, tt.mockError
 				},
 			}
 			service := NewWorkHistoryService(mockRepo)
 
 			// Act
-			result, err := service.Create(tt.wh)
+			result, err := service.Create(tt.wh, nil)
 
 			// Assert
 			if tt.wantError {
@@ -413,14 +452,14 @@ func TestWorkHistoryService_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
 			mockRepo := &MockWorkHistoryRepo{
-				UpdateFunc: func(wh models.WorkHistory) (models.WorkHistory, error) {
+				UpdateFunc: func(wh models.WorkHistory, technologyIDs []int64) (models.WorkHistory, error) {
 					return tt.mockWH, tt.mockError
 				},
 			}
 			service := NewWorkHistoryService(mockRepo)
 
 			// Act
-			result, err := service.Update(tt.wh)
+			result, err := service.Update(tt.wh, nil)
 
 			// Assert
 			if tt.wantError {
@@ -574,3 +613,182 @@ func TestWorkHistoryService_DeleteList(t *testing.T) {
 		})
 	}
 }
+
+// TestWorkHistoryService_GetWithTechnologies проверяет, что Get возвращает технологии записи
+func TestWorkHistoryService_GetWithTechnologies(t *testing.T) {
+	mockRepo := &MockWorkHistoryRepo{
+		GetFunc: func(id int64) (models.WorkHistory, error) {
+			return models.WorkHistory{ID: id, Name: "Яндекс"}, nil
+		},
+		TechnologiesFunc: func(whID int64) ([]models.Technology, error) {
+			return []models.Technology{{ID: 1, Title: "Go"}, {ID: 2, Title: "PostgreSQL"}}, nil
+		},
+	}
+	service := NewWorkHistoryService(mockRepo)
+
+	result, err := service.Get(7)
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if result.ID != 7 || len(result.Technologies) != 2 {
+		t.Errorf("Ожидалась запись 7 с 2 технологиями, получили %d с %d", result.ID, len(result.Technologies))
+	}
+
+	mockRepo.TechnologiesFunc = func(whID int64) ([]models.Technology, error) {
+		return nil, errors.New("database error")
+	}
+	if _, err := service.Get(7); err == nil || !contains(err.Error(), "error getting work history technologies") {
+		t.Errorf("Ожидалась ошибка получения технологий, получили: %v", err)
+	}
+}
+
+// TestWorkHistoryService_CreateWithTechnologies проверяет передачу technologyIds в репозиторий
+func TestWorkHistoryService_CreateWithTechnologies(t *testing.T) {
+	var gotIDs []int64
+	mockRepo := &MockWorkHistoryRepo{
+		CreateFunc: func(wh models.WorkHistory, technologyIDs []int64) (models.WorkHistory, error) {
+			gotIDs = technologyIDs
+			wh.ID = 1
+			return wh, nil
+		},
+	}
+	service := NewWorkHistoryService(mockRepo)
+
+	_, err := service.Create(models.WorkHistory{Name: "Яндекс", About: "Backend"}, []int64{3, 5})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if len(gotIDs) != 2 || gotIDs[0] != 3 || gotIDs[1] != 5 {
+		t.Errorf("В репозиторий переданы технологии %v, ожидались [3 5]", gotIDs)
+	}
+
+	missing := &entityreqdecorator.MissingError{Entity: "technology", IDs: []int64{9}}
+	mockRepo.CreateFunc = func(wh models.WorkHistory, technologyIDs []int64) (models.WorkHistory, error) {
+		return models.WorkHistory{}, missing
+	}
+	_, err = service.Create(models.WorkHistory{Name: "Яндекс", About: "Backend"}, []int64{9})
+	var gotMissing *entityreqdecorator.MissingError
+	if !errors.As(err, &gotMissing) {
+		t.Errorf("Ожидалась MissingError, получили: %v", err)
+	}
+}
+
+// TestWorkHistoryService_SetTechnologies тестирует метод SetTechnologies
+func TestWorkHistoryService_SetTechnologies(t *testing.T) {
+	notFound := fmt.Errorf("work history with id 404 %w", entityreqdecorator.ErrNotFound)
+	mockRepo := &MockWorkHistoryRepo{
+		SetTechnologiesFunc: func(whID int64, technologyIDs []int64) ([]models.Technology, error) {
+			if whID == 404 {
+				return nil, notFound
+			}
+			return []models.Technology{{ID: technologyIDs[0]}}, nil
+		},
+	}
+	service := NewWorkHistoryService(mockRepo)
+
+	technologies, err := service.SetTechnologies(1, []int64{3})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if len(technologies) != 1 || technologies[0].ID != 3 {
+		t.Errorf("Ожидалась технология 3, получили %v", technologies)
+	}
+
+	if _, err := service.SetTechnologies(404, []int64{3}); !errors.Is(err, entityreqdecorator.ErrNotFound) {
+		t.Errorf("Ожидалась ErrNotFound, получили: %v", err)
+	}
+	if _, err := service.SetTechnologies(0, []int64{3}); err == nil {
+		t.Errorf("Ожидалась ошибка для ID 0")
+	}
+}
+
+// TestWorkHistoryService_Expand проверяет, что include подгружает связи одним запросом на уровень
+func TestWorkHistoryService_Expand(t *testing.T) {
+	var techCalls, tagCalls int
+	var gotTechIDs []int64
+	mockRepo := &MockWorkHistoryRepo{
+		TechnologiesByWorkHistoryFunc: func(whIDs []int64) (map[int64][]models.Technology, error) {
+			techCalls++
+			return map[int64][]models.Technology{
+				1: {{ID: 10, Title: "Go"}, {ID: 11, Title: "PostgreSQL"}},
+				2: {{ID: 10, Title: "Go"}},
+			}, nil
+		},
+		TagsByTechnologyFunc: func(techIDs []int64) (map[int64][]models.Tag, error) {
+			tagCalls++
+			gotTechIDs = techIDs
+			return map[int64][]models.Tag{10: {{ID: 100, Name: "Backend"}}}, nil
+		},
+	}
+	service := NewWorkHistoryService(mockRepo)
+	items := []models.WorkHistory{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	expanded, err := service.Expand(items, []string{"technologies"})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if techCalls != 1 || tagCalls != 0 {
+		t.Errorf("Ожидался 1 запрос технологий и 0 тегов, получили %d и %d", techCalls, tagCalls)
+	}
+	if technologies := expanded[2].Relations["technologies"].([]models.Technology); technologies == nil || len(technologies) != 0 {
+		t.Errorf("Для записи без технологий ожидался пустой список, получили %v", technologies)
+	}
+
+	expanded, err = service.Expand(items, []string{"technologies", "technologies.tags"})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if techCalls != 2 || tagCalls != 1 {
+		t.Errorf("Ожидалось по одному запросу на уровень, получили %d и %d", techCalls-1, tagCalls)
+	}
+	if len(gotTechIDs) != 3 {
+		t.Errorf("Теги запрошены для %v, ожидались технологии всех записей", gotTechIDs)
+	}
+	technologies := expanded[0].Relations["technologies"].([]entityreqdecorator.Expanded[models.Technology])
+	if tags := technologies[0].Relations["tags"].([]models.Tag); len(tags) != 1 || tags[0].ID != 100 {
+		t.Errorf("Ожидался тег 100 у технологии 10, получили %v", tags)
+	}
+	if tags := technologies[1].Relations["tags"].([]models.Tag); len(tags) != 0 {
+		t.Errorf("Ожидался пустой список тегов, получили %v", tags)
+	}
+}
+
+// TestWorkHistoryService_ExpandOne проверяет, что технологии записи из Get не запрашиваются повторно
+func TestWorkHistoryService_ExpandOne(t *testing.T) {
+	var techCalls, tagCalls int
+	mockRepo := &MockWorkHistoryRepo{
+		TechnologiesByWorkHistoryFunc: func(whIDs []int64) (map[int64][]models.Technology, error) {
+			techCalls++
+			return nil, nil
+		},
+		TagsByTechnologyFunc: func(techIDs []int64) (map[int64][]models.Tag, error) {
+			tagCalls++
+			return map[int64][]models.Tag{10: {{ID: 100, Name: "Backend"}}}, nil
+		},
+	}
+	service := NewWorkHistoryService(mockRepo)
+	item := WorkHistoryWithTechnologies{
+		WorkHistory:  models.WorkHistory{ID: 1},
+		Technologies: []models.Technology{{ID: 10, Title: "Go"}},
+	}
+
+	expanded, err := service.ExpandOne(item, []string{"technologies"})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if technologies := expanded.Relations["technologies"].([]models.Technology); len(technologies) != 1 || technologies[0].ID != 10 {
+		t.Errorf("Ожидались технологии из Get, получили %v", technologies)
+	}
+
+	expanded, err = service.ExpandOne(item, []string{"technologies", "technologies.tags"})
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получили: %v", err)
+	}
+	if techCalls != 0 || tagCalls != 1 {
+		t.Errorf("Ожидалось 0 запросов технологий и 1 запрос тегов, получили %d и %d", techCalls, tagCalls)
+	}
+	technologies := expanded.Relations["technologies"].([]entityreqdecorator.Expanded[models.Technology])
+	if tags := technologies[0].Relations["tags"].([]models.Tag); len(tags) != 1 || tags[0].ID != 100 {
+		t.Errorf("Ожидался тег 100 у технологии 10, получили %v", tags)
+	}
+}
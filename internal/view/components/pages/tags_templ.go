@@ -13,7 +13,7 @@ import "github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 import "github.com/Maxim-Ba/cv-backend/internal/models/gen"
 import "github.com/Maxim-Ba/cv-backend/internal/view/components/components"
 
-func TagPage(user string, tagsResult entityreqdecorator.PagebleRs[models.Tag]) templ.Component {
+func TagPage(session components.AdminSession, tagsResult entityreqdecorator.PagebleRs[models.Tag]) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -34,7 +34,7 @@ func TagPage(user string, tagsResult entityreqdecorator.PagebleRs[models.Tag]) t
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = layout.Base("Tag", tagPage(user, tagsResult), user).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = layout.Base("Tag", tagPage(session.User, tagsResult), session).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
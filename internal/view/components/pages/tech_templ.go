@@ -15,7 +15,7 @@ import (
 	"github.com/Maxim-Ba/cv-backend/pkg/entity-req-decorator"
 )
 
-func TechPage(user string, techResult entityreqdecorator.PagebleRs[models.Technology], editID string, csrfToken string) templ.Component {
+func TechPage(session components.AdminSession, techResult entityreqdecorator.PagebleRs[models.Technology], editID string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -36,7 +36,7 @@ func TechPage(user string, techResult entityreqdecorator.PagebleRs[models.Techno
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = layout.Base("Tech", techPage(techResult, editID, csrfToken), user).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = layout.Base("Tech", techPage(techResult, editID, session.CSRFToken), session).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -0,0 +1,17 @@
+CREATE TABLE
+  IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  );
+
+CREATE TABLE
+  IF NOT EXISTS sessions (
+    id TEXT PRIMARY KEY, -- sha256 от токена из cookie
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+  );
+
+CREATE INDEX IF NOT EXISTS sessions_expires_at_idx ON sessions (expires_at);
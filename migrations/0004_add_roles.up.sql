@@ -0,0 +1,45 @@
+CREATE TABLE
+  IF NOT EXISTS roles (
+    name TEXT PRIMARY KEY,
+    title TEXT NOT NULL
+  );
+
+CREATE TABLE
+  IF NOT EXISTS role_permissions (
+    role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission)
+  );
+
+INSERT INTO roles (name, title)
+VALUES
+  ('viewer', 'Наблюдатель'),
+  ('editor', 'Редактор'),
+  ('owner', 'Владелец')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES
+  ('viewer', 'admin:read'),
+  ('editor', 'admin:read'),
+  ('editor', 'tag:write'),
+  ('editor', 'tech:write'),
+  ('editor', 'wh:write'),
+  ('editor', 'wh:delete'),
+  ('editor', 'edu:write'),
+  ('editor', 'edu:delete'),
+  ('owner', 'admin:read'),
+  ('owner', 'tag:write'),
+  ('owner', 'tag:delete'),
+  ('owner', 'tech:write'),
+  ('owner', 'tech:delete'),
+  ('owner', 'wh:write'),
+  ('owner', 'wh:delete'),
+  ('owner', 'edu:write'),
+  ('owner', 'edu:delete'),
+  ('owner', 'user:manage')
+ON CONFLICT DO NOTHING;
+
+-- Пользователи, созданные до появления ролей, становятся владельцами
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'owner' REFERENCES roles (name);
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'viewer';
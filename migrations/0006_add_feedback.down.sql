@@ -0,0 +1,3 @@
+DELETE FROM role_permissions WHERE permission = 'fb:read';
+
+DROP TABLE IF EXISTS feedback;
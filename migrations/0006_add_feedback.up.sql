@@ -0,0 +1,20 @@
+CREATE TABLE
+  IF NOT EXISTS feedback (
+    id BIGSERIAL PRIMARY KEY,
+    author_name TEXT NOT NULL,
+    contact TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status TEXT NOT NULL DEFAULT 'new',
+    source_ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT ''
+  );
+
+CREATE INDEX IF NOT EXISTS feedback_created_at_idx ON feedback (created_at);
+
+-- Сообщения посетителей содержат контакты, поэтому читать их могут только редактор и владелец
+INSERT INTO role_permissions (role, permission)
+VALUES
+  ('editor', 'fb:read'),
+  ('owner', 'fb:read')
+ON CONFLICT DO NOTHING;
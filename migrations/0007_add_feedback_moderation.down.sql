@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS feedback_status_idx;
+
+DELETE FROM role_permissions WHERE permission = 'fb:write';
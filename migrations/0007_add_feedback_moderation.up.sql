@@ -0,0 +1,8 @@
+-- Модерация обратной связи: смена статусов new/read/replied/spam/archived
+INSERT INTO role_permissions (role, permission)
+VALUES
+  ('editor', 'fb:write'),
+  ('owner', 'fb:write')
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS feedback_status_idx ON feedback (status);
@@ -0,0 +1,6 @@
+-- Загрузка файлов (логотипов) через POST /api/files
+INSERT INTO role_permissions (role, permission)
+VALUES
+  ('editor', 'file:write'),
+  ('owner', 'file:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE work_history DROP CONSTRAINT IF EXISTS work_history_logo_url_check;
@@ -0,0 +1,16 @@
+-- logo_url места работы хранит варианты логотипа {original, sm, md, lg}.
+-- Старые значения (строка или {"url": ...}) становятся оригиналом без уменьшенных копий.
+UPDATE work_history
+SET logo_url = jsonb_build_object('original', logo_url #>> '{}')
+WHERE jsonb_typeof(logo_url) = 'string';
+
+UPDATE work_history
+SET logo_url = jsonb_build_object('original', logo_url ->> 'url')
+WHERE jsonb_typeof(logo_url) = 'object'
+  AND NOT logo_url ? 'original'
+  AND logo_url ? 'url';
+
+-- NOT VALID: существующие записи неизвестного формата не мешают миграции
+ALTER TABLE work_history
+ADD CONSTRAINT work_history_logo_url_check
+CHECK (logo_url IS NULL OR (jsonb_typeof(logo_url) = 'object' AND logo_url ? 'original')) NOT VALID;
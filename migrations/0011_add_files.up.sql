@@ -0,0 +1,16 @@
+-- Загруженные файлы. Одна запись на загрузку: оригинал и его уменьшенные копии.
+-- Файл считается используемым, пока один из его URL указан в technology.logo_url
+-- или среди вариантов work_history.logo_url.
+CREATE TABLE
+  IF NOT EXISTS files (
+    id BIGSERIAL PRIMARY KEY,
+    key TEXT NOT NULL UNIQUE,
+    url TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    keys TEXT[] NOT NULL DEFAULT '{}',
+    urls TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  );
+
+CREATE INDEX IF NOT EXISTS files_created_at_idx ON files (created_at);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS education_search_idx;
+DROP INDEX IF EXISTS work_history_search_idx;
+DROP INDEX IF EXISTS technology_search_idx;
+
+ALTER TABLE education DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE work_history DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE technology DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS search_array_text(TEXT[]);
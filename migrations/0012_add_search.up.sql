@@ -0,0 +1,33 @@
+-- Полнотекстовый поиск по резюме (GET /api/search).
+-- Конфигурация russian стеммит кириллицу русским стеммером, а слова латиницей — английским,
+-- поэтому один вектор находит "разработка"/"разработал" и "developing"/"developer".
+-- Веса: A — заголовок, B — описание, C — списки задач и проектов.
+
+-- array_to_string не IMMUTABLE и не может использоваться в генерируемых колонках
+CREATE OR REPLACE FUNCTION search_array_text(arr TEXT[]) RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$ SELECT coalesce(array_to_string(arr, ' '), '') $$;
+
+ALTER TABLE technology
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('russian', title), 'A') ||
+  setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE work_history
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('russian', name), 'A') ||
+  setweight(to_tsvector('russian', about), 'B') ||
+  setweight(to_tsvector('russian', search_array_text(what_i_did)), 'C') ||
+  setweight(to_tsvector('russian', search_array_text(projects)), 'C')
+) STORED;
+
+ALTER TABLE education
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('russian', course), 'A') ||
+  setweight(to_tsvector('russian', organization), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS technology_search_idx ON technology USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS work_history_search_idx ON work_history USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS education_search_idx ON education USING GIN (search_vector);
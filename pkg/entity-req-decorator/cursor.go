@@ -0,0 +1,247 @@
+package entityreqdecorator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// cursorToken содержимое курсора: сортировка, для которой он выдан,
+// значения ключей сортировки крайней строки и направление движения
+type cursorToken struct {
+	Sort     []SortBy `json:"s"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// encodeCursor кодирует курсор в непрозрачную для клиента строку
+func encodeCursor(t cursorToken) string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor разбирает курсор, полученный от клиента
+func decodeCursor(s string) (cursorToken, error) {
+	var t cursorToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// keyset параметры курсорной пагинации, построенной по ключам сортировки
+type keyset struct {
+	fields []Field
+	// sort сортировка по именам колонок в порядке, запрошенном клиентом
+	sort      []SortBy
+	backward  bool
+	hasCursor bool
+	size      int
+}
+
+// addKeyset добавляет сортировку, условие "после курсора" и LIMIT для курсорной пагинации.
+// К запрошенной сортировке добавляется id, чтобы порядок строк был однозначным.
+// Запрашивается на одну строку больше size, чтобы узнать, есть ли следующая страница.
+// Возвращает примененные сортировки с публичными именами полей.
+func (qb *QueryBuilder) addKeyset(req PagebleRq, schema Schema) (*keyset, []SortBy, error) {
+	errs := &QueryError{}
+	ks := &keyset{size: req.Size}
+	var applied []SortBy
+	hasID := false
+	for _, s := range req.Sort {
+		f, ok := schema.Lookup(s.Field)
+		if !ok || !f.Sortable {
+			continue
+		}
+		if f.Nullable {
+			errs.add("sort", "cursor pagination cannot sort by nullable field %q", s.Field)
+			continue
+		}
+		hasID = hasID || f.Name == "id"
+		ks.fields = append(ks.fields, f)
+		ks.sort = append(ks.sort, SortBy{Field: f.Name, Order: s.Order})
+		applied = append(applied, SortBy{Field: f.PublicName(), Order: s.Order})
+	}
+	if !hasID {
+		id, ok := schema.Lookup("id")
+		if !ok {
+			return nil, nil, fmt.Errorf("cursor pagination requires an id field in the schema")
+		}
+		ks.fields = append(ks.fields, id)
+		ks.sort = append(ks.sort, SortBy{Field: id.Name, Order: "ASC"})
+		applied = append(applied, SortBy{Field: id.PublicName(), Order: "ASC"})
+	}
+
+	var values []interface{}
+	if req.Cursor != "" {
+		token, err := decodeCursor(req.Cursor)
+		switch {
+		case err != nil || len(token.Values) != len(ks.fields):
+			errs.add("cursor", "invalid cursor")
+		case !slices.Equal(token.Sort, ks.sort):
+			errs.add("cursor", "cursor does not match sort")
+		default:
+			for i, f := range ks.fields {
+				v, err := f.Parse(token.Values[i])
+				if err != nil {
+					errs.add("cursor", "invalid cursor")
+					break
+				}
+				values = append(values, v)
+			}
+		}
+		ks.backward = token.Backward
+		ks.hasCursor = true
+	}
+	if err := errs.orNil(); err != nil {
+		return nil, nil, err
+	}
+
+	// При движении назад строки выбираются в обратном порядке и переворачиваются в NewPage
+	orders := make([]string, len(ks.sort))
+	orderBy := make([]string, len(ks.sort))
+	for i, s := range ks.sort {
+		orders[i] = s.Order
+		if ks.backward {
+			orders[i] = reverseOrder(s.Order)
+		}
+		orderBy[i] = ks.fields[i].Name + " " + orders[i]
+	}
+	if values != nil {
+		qb.WhereConditions = append(qb.WhereConditions, qb.keysetCondition(ks.fields, orders, values))
+	}
+	qb.OrderByClause = " ORDER BY " + strings.Join(orderBy, ", ")
+	if req.Size > 0 {
+		qb.LimitClause = " LIMIT " + qb.bind(req.Size+1)
+	}
+	return ks, applied, nil
+}
+
+// keysetCondition строит условие "строка после курсора". Если все колонки
+// сортируются в одном направлении, используется сравнение строк:
+// "(period_start, id) > ($1, $2)", иначе условие раскрывается:
+// "(period_start < $1 OR (period_start = $2 AND id > $3))".
+func (qb *QueryBuilder) keysetCondition(fields []Field, orders []string, values []interface{}) string {
+	op := func(order string) string {
+		if order == "DESC" {
+			return "<"
+		}
+		return ">"
+	}
+
+	uniform := true
+	for _, order := range orders {
+		uniform = uniform && order == orders[0]
+	}
+	if uniform {
+		columns := make([]string, len(fields))
+		placeholders := make([]string, len(fields))
+		for i, f := range fields {
+			columns[i] = f.Name
+			placeholders[i] = qb.bind(values[i])
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op(orders[0]), strings.Join(placeholders, ", "))
+	}
+
+	var alternatives []string
+	for i := range fields {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fields[j].Name+" = "+qb.bind(values[j]))
+		}
+		parts = append(parts, fields[i].Name+" "+op(orders[i])+" "+qb.bind(values[i]))
+		if len(parts) == 1 {
+			alternatives = append(alternatives, parts[0])
+		} else {
+			alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+		}
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+func reverseOrder(order string) string {
+	if order == "DESC" {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// cursor строит курсор по значениям ключей сортировки строки. Значения берутся
+// из JSON-представления строки по публичным именам полей.
+func (ks *keyset) cursor(row interface{}, backward bool) (string, error) {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	token := cursorToken{Sort: ks.sort, Backward: backward}
+	for _, f := range ks.fields {
+		raw, ok := fields[f.PublicName()]
+		if !ok || string(raw) == "null" {
+			return "", fmt.Errorf("failed to encode cursor: no value for %s", f.PublicName())
+		}
+		value := string(raw)
+		if raw[0] == '"' {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return "", fmt.Errorf("failed to encode cursor: %w", err)
+			}
+		}
+		token.Values = append(token.Values, value)
+	}
+	return encodeCursor(token), nil
+}
+
+// NewPage собирает ответ списочного запроса. В режиме курсора отбрасывается лишняя
+// строка, запрошенная для проверки наличия следующей страницы, страница, выбранная
+// при движении назад, переворачивается, а по крайним строкам строятся nextCursor и prevCursor.
+func NewPage[T any](req PagebleRq, q *ListQuery, items []T, total int) (PagebleRs[T], error) {
+	rs := PagebleRs[T]{
+		Total:   total,
+		Content: items,
+		Page:    req.Page,
+		Size:    req.Size,
+		Sort:    q.Sort,
+		Fields:  q.Fields,
+	}
+	ks := q.keyset
+	if ks == nil {
+		return rs, nil
+	}
+
+	rs.Page = 0
+	more := ks.size > 0 && len(items) > ks.size
+	if more {
+		items = items[:ks.size]
+	}
+	if ks.backward {
+		slices.Reverse(items)
+	}
+	rs.Content = items
+	if len(items) == 0 {
+		return rs, nil
+	}
+
+	var err error
+	// Следующая страница есть, если вперед выбрано больше size строк или к этой странице пришли с конца
+	if more || ks.backward {
+		if rs.NextCursor, err = ks.cursor(items[len(items)-1], false); err != nil {
+			return PagebleRs[T]{}, err
+		}
+	}
+	// Предыдущая страница есть, если это не первая страница
+	if ks.hasCursor && !ks.backward || more && ks.backward {
+		if rs.PrevCursor, err = ks.cursor(items[0], true); err != nil {
+			return PagebleRs[T]{}, err
+		}
+	}
+	return rs, nil
+}
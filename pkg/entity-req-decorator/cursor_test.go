@@ -0,0 +1,212 @@
+package entityreqdecorator
+
+import (
+	"errors"
+	"testing"
+)
+
+type testRow struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	PeriodStart string `json:"periodStart"`
+}
+
+var cursorSchema = Schema{
+	{Name: "id", Type: TypeInt, Filterable: true, Sortable: true},
+	{Name: "name", Type: TypeText, Filterable: true, Sortable: true},
+	{Name: "period_start", Alias: "periodStart", Type: TypeDate, Filterable: true, Sortable: true},
+	{Name: "period_end", Alias: "periodEnd", Type: TypeDate, Filterable: true, Sortable: true, Nullable: true},
+}
+
+func TestBuildListQueryCursorFirstPage(t *testing.T) {
+	req, err := ParseQueryParamsStrict(map[string][]string{
+		"cursor": {""},
+		"size":   {"2"},
+		"sort":   {"periodStart,desc"},
+		"name":   {"ilike(go)"},
+	})
+	if err != nil {
+		t.Fatalf("ParseQueryParamsStrict() error = %v", err)
+	}
+
+	q, err := BuildListQuery(req, "SELECT * FROM work_history", cursorSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery := "SELECT * FROM work_history WHERE name ILIKE $1 ORDER BY period_start DESC, id ASC LIMIT $2"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	if q.CountQuery != "" {
+		t.Errorf("CountQuery = %v, want empty without withTotal", q.CountQuery)
+	}
+	if q.SelectParams[1] != 3 {
+		t.Errorf("LIMIT param = %v, want size+1", q.SelectParams[1])
+	}
+
+	rows := []testRow{
+		{ID: 3, Name: "Go", PeriodStart: "2023-01-01"},
+		{ID: 1, Name: "Go", PeriodStart: "2021-01-01"},
+		{ID: 2, Name: "Go", PeriodStart: "2019-01-01"},
+	}
+	rs, err := NewPage(req, q, rows, 0)
+	if err != nil {
+		t.Fatalf("NewPage() error = %v", err)
+	}
+	if len(rs.Content) != 2 || rs.Content[1].ID != 1 {
+		t.Errorf("Content = %v, want first two rows", rs.Content)
+	}
+	if rs.NextCursor == "" || rs.PrevCursor != "" {
+		t.Errorf("NextCursor = %q, PrevCursor = %q, want only next", rs.NextCursor, rs.PrevCursor)
+	}
+	wantSort := []SortBy{{Field: "periodStart", Order: "DESC"}, {Field: "id", Order: "ASC"}}
+	if len(rs.Sort) != 2 || rs.Sort[0] != wantSort[0] || rs.Sort[1] != wantSort[1] {
+		t.Errorf("Sort = %v, want %v", rs.Sort, wantSort)
+	}
+
+	// Следующая страница строится от последней строки
+	next, err := ParseQueryParamsStrict(map[string][]string{
+		"cursor": {rs.NextCursor},
+		"size":   {"2"},
+		"sort":   {"periodStart,desc"},
+	})
+	if err != nil {
+		t.Fatalf("ParseQueryParamsStrict() error = %v", err)
+	}
+	q, err = BuildListQuery(next, "SELECT * FROM work_history", cursorSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery = "SELECT * FROM work_history WHERE (period_start < $1 OR (period_start = $2 AND id > $3)) ORDER BY period_start DESC, id ASC LIMIT $4"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	if q.SelectParams[2] != int64(1) {
+		t.Errorf("cursor id param = %v, want 1", q.SelectParams[2])
+	}
+
+	rs2, err := NewPage(next, q, []testRow{{ID: 2, Name: "Go", PeriodStart: "2019-01-01"}}, 0)
+	if err != nil {
+		t.Fatalf("NewPage() error = %v", err)
+	}
+	if rs2.NextCursor != "" || rs2.PrevCursor == "" {
+		t.Errorf("NextCursor = %q, PrevCursor = %q, want only prev on the last page", rs2.NextCursor, rs2.PrevCursor)
+	}
+
+	// Назад: порядок сортировки переворачивается, строки возвращаются в исходном порядке
+	prev, _ := ParseQueryParamsStrict(map[string][]string{
+		"cursor": {rs2.PrevCursor},
+		"size":   {"2"},
+		"sort":   {"periodStart,desc"},
+	})
+	q, err = BuildListQuery(prev, "SELECT * FROM work_history", cursorSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery = "SELECT * FROM work_history WHERE (period_start > $1 OR (period_start = $2 AND id < $3)) ORDER BY period_start ASC, id DESC LIMIT $4"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	rs3, err := NewPage(prev, q, []testRow{rows[1], rows[0]}, 0)
+	if err != nil {
+		t.Fatalf("NewPage() error = %v", err)
+	}
+	if rs3.Content[0].ID != 3 || rs3.Content[1].ID != 1 {
+		t.Errorf("Content = %v, want rows in sort order", rs3.Content)
+	}
+	if rs3.NextCursor == "" || rs3.PrevCursor != "" {
+		t.Errorf("NextCursor = %q, PrevCursor = %q, want only next on the first page", rs3.NextCursor, rs3.PrevCursor)
+	}
+}
+
+func TestBuildListQueryCursorUniformOrder(t *testing.T) {
+	token := encodeCursor(cursorToken{
+		Sort:   []SortBy{{Field: "name", Order: "ASC"}, {Field: "id", Order: "ASC"}},
+		Values: []string{"Go", "7"},
+	})
+	req := PagebleRq{
+		Size:       10,
+		CursorMode: true,
+		Cursor:     token,
+		WithTotal:  true,
+		Sort:       []SortBy{{Field: "name", Order: "ASC"}},
+		Filter: map[string]SQLGenerator{
+			"id": &PredicateGT{Predicate: Predicate{Value: "2"}},
+		},
+	}
+	q, err := BuildListQuery(req, "SELECT * FROM t", cursorSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery := "SELECT * FROM t WHERE id > $1 AND (name, id) > ($2, $3) ORDER BY name ASC, id ASC LIMIT $4"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	wantCount := "SELECT COUNT(*) FROM (SELECT * FROM t WHERE id > $1) as subquery"
+	if q.CountQuery != wantCount || len(q.CountParams) != 1 {
+		t.Errorf("CountQuery = %v %v, want %v without cursor condition", q.CountQuery, q.CountParams, wantCount)
+	}
+}
+
+func TestBuildListQueryCursorErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    PagebleRq
+		wantIn string
+	}{
+		{
+			name:   "garbage cursor",
+			req:    PagebleRq{Size: 10, CursorMode: true, Cursor: "not a cursor"},
+			wantIn: "cursor",
+		},
+		{
+			name: "cursor for another sort",
+			req: PagebleRq{
+				Size:       10,
+				CursorMode: true,
+				Cursor: encodeCursor(cursorToken{
+					Sort:   []SortBy{{Field: "id", Order: "ASC"}},
+					Values: []string{"7"},
+				}),
+				Sort: []SortBy{{Field: "id", Order: "DESC"}},
+			},
+			wantIn: "cursor",
+		},
+		{
+			name: "nullable sort field",
+			req: PagebleRq{
+				Size:       10,
+				CursorMode: true,
+				Sort:       []SortBy{{Field: "periodEnd", Order: "ASC"}},
+			},
+			wantIn: "sort",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildListQuery(tt.req, "SELECT * FROM t", cursorSchema)
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				t.Fatalf("BuildListQuery() error = %v, want *QueryError", err)
+			}
+			if qe.Params[0].Param != tt.wantIn {
+				t.Errorf("QueryError param = %v, want %v", qe.Params[0].Param, tt.wantIn)
+			}
+		})
+	}
+}
+
+func TestParseQueryParamsCursor(t *testing.T) {
+	_, err := ParseQueryParamsStrict(map[string][]string{
+		"cursor":    {""},
+		"page":      {"2"},
+		"withTotal": {"maybe"},
+	})
+	var qe *QueryError
+	if !errors.As(err, &qe) || len(qe.Params) != 2 {
+		t.Fatalf("ParseQueryParamsStrict() error = %v, want page and withTotal errors", err)
+	}
+	if qe.Params[0].Param != "page" || qe.Params[1].Param != "withTotal" {
+		t.Errorf("QueryError params = %v", qe.Params)
+	}
+}
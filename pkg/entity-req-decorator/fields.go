@@ -0,0 +1,164 @@
+package entityreqdecorator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseFields разбирает параметр fields=id,name,periodStart.
+// Возвращает nil, если параметр не передан: нужны все поля.
+func ParseFields(queryParams map[string][]string) []string {
+	values, ok := queryParams["fields"]
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	var fields []string
+	for _, field := range strings.Split(values[0], ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// SelectColumns возвращает колонки, которые нужно выбрать для req.Fields, в порядке схемы.
+// Кроме запрошенных выбираются id и колонки сортировки: они нужны для курсора.
+// Без req.Fields выбираются все колонки схемы.
+func SelectColumns(req PagebleRq, schema Schema) ([]Field, error) {
+	if len(req.Fields) == 0 {
+		return schema.Columns(), nil
+	}
+	errs := &QueryError{}
+	selected := map[string]bool{"id": true}
+	for _, name := range req.Fields {
+		f, ok := schema.Lookup(name)
+		if !ok || f.Relation != nil {
+			errs.add("fields", "unknown field %q", name)
+			continue
+		}
+		selected[f.Name] = true
+	}
+	if err := errs.orNil(); err != nil {
+		return nil, err
+	}
+	for _, s := range req.Sort {
+		if f, ok := schema.Lookup(s.Field); ok && f.Relation == nil {
+			selected[f.Name] = true
+		}
+	}
+
+	var columns []Field
+	for _, f := range schema {
+		if selected[f.Name] {
+			columns = append(columns, f)
+		}
+	}
+	return columns, nil
+}
+
+// ColumnList возвращает список колонок для SELECT: "id, name, period_start"
+func ColumnList(columns []Field) string {
+	names := make([]string, len(columns))
+	for i, f := range columns {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// ScanTargets возвращает аргументы rows.Scan для выбранных колонок.
+// targets сопоставляет имя колонки с полем модели.
+func ScanTargets(columns []Field, targets map[string]interface{}) []interface{} {
+	dest := make([]interface{}, len(columns))
+	for i, f := range columns {
+		dest[i] = targets[f.Name]
+	}
+	return dest
+}
+
+// Project оставляет в JSON-представлении v только поля fields. Имена полей
+// разбираются по схеме, как в списках: принимаются и имена из JSON, и колонки.
+// Поле не из схемы возвращается как *QueryError.
+func Project(v interface{}, fields []string, schema Schema) (map[string]json.RawMessage, error) {
+	public, errs := publicFields(fields, schema)
+	if err := errs.orNil(); err != nil {
+		return nil, err
+	}
+	return project(v, public)
+}
+
+// project оставляет в JSON-представлении v только поля fields (имена из JSON)
+func project(v interface{}, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", v, err)
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", v, err)
+	}
+	errs := &QueryError{}
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		value, ok := all[field]
+		if !ok {
+			errs.add("fields", "unknown field %q", field)
+			continue
+		}
+		projected[field] = value
+	}
+	// Связи из include попадают в ответ вместе с выбранными полями
+	if e, ok := v.(interface{ relationNames() []string }); ok {
+		for _, name := range e.relationNames() {
+			projected[name] = all[name]
+		}
+	}
+	if err := errs.orNil(); err != nil {
+		return nil, err
+	}
+	return projected, nil
+}
+
+// publicFields переводит имена полей из запроса в имена из JSON, убирая повторы
+func publicFields(fields []string, schema Schema) ([]string, *QueryError) {
+	errs := &QueryError{}
+	var public []string
+	for _, name := range fields {
+		f, ok := schema.Lookup(name)
+		if !ok || f.Relation != nil {
+			errs.add("fields", "unknown field %q", name)
+			continue
+		}
+		if !slices.Contains(public, f.PublicName()) {
+			public = append(public, f.PublicName())
+		}
+	}
+	return public, errs
+}
+
+// MarshalJSON реализует json.Marshaler. Если задан Fields, в элементах Content
+// остаются только эти поля.
+func (rs PagebleRs[T]) MarshalJSON() ([]byte, error) {
+	var content interface{} = rs.Content
+	if len(rs.Fields) > 0 && rs.Content != nil {
+		projected := make([]map[string]json.RawMessage, len(rs.Content))
+		for i, item := range rs.Content {
+			p, err := project(item, rs.Fields)
+			if err != nil {
+				return nil, err
+			}
+			projected[i] = p
+		}
+		content = projected
+	}
+	return json.Marshal(struct {
+		Total      int         `json:"total,omitempty"`
+		Content    interface{} `json:"content"`
+		Page       int         `json:"page,omitempty"`
+		Size       int         `json:"size,omitempty"`
+		Sort       []SortBy    `json:"sort,omitempty"`
+		NextCursor string      `json:"nextCursor,omitempty"`
+		PrevCursor string      `json:"prevCursor,omitempty"`
+	}{rs.Total, content, rs.Page, rs.Size, rs.Sort, rs.NextCursor, rs.PrevCursor})
+}
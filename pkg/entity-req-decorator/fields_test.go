@@ -0,0 +1,127 @@
+package entityreqdecorator
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var fieldsSchema = Schema{
+	{Name: "id", Type: TypeInt, Filterable: true, Sortable: true},
+	{Name: "name", Type: TypeText, Filterable: true, Sortable: true},
+	{Name: "about", Type: TypeText, Filterable: true},
+	{Name: "period_start", Alias: "periodStart", Type: TypeDate, Filterable: true, Sortable: true},
+	{Name: "what_i_did", Alias: "whatIDid", Type: TypeTextArray},
+}
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     PagebleRq
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "all columns without fields",
+			req:  PagebleRq{},
+			want: "id, name, about, period_start, what_i_did",
+		},
+		{
+			name: "requested fields with id and sort key",
+			req: PagebleRq{
+				Fields: []string{"name", "whatIDid"},
+				Sort:   []SortBy{{Field: "periodStart", Order: "DESC"}},
+			},
+			want: "id, name, period_start, what_i_did",
+		},
+		{
+			name:    "unknown field",
+			req:     PagebleRq{Fields: []string{"name", "salary"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := SelectColumns(tt.req, fieldsSchema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := ColumnList(columns); !tt.wantErr && got != tt.want {
+				t.Errorf("ColumnList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanTargets(t *testing.T) {
+	var row testRow
+	targets := map[string]interface{}{"id": &row.ID, "name": &row.Name, "period_start": &row.PeriodStart}
+	dest := ScanTargets(Schema{{Name: "name"}, {Name: "id"}}, targets)
+	if len(dest) != 2 || dest[0] != &row.Name || dest[1] != &row.ID {
+		t.Errorf("ScanTargets() = %v, want pointers in column order", dest)
+	}
+}
+
+func TestProject(t *testing.T) {
+	row := testRow{ID: 1, Name: "Go", PeriodStart: "2019-01-01"}
+	got, err := Project(row, []string{"id", "periodStart"}, fieldsSchema)
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+	data, _ := json.Marshal(got)
+	if want := `{"id":1,"periodStart":"2019-01-01"}`; string(data) != want {
+		t.Errorf("Project() = %s, want %s", data, want)
+	}
+
+	// Имена колонок разбираются по схеме, как в списках
+	got, err = Project(row, []string{"period_start", "periodStart"}, fieldsSchema)
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+	data, _ = json.Marshal(got)
+	if want := `{"periodStart":"2019-01-01"}`; string(data) != want {
+		t.Errorf("Project() = %s, want %s", data, want)
+	}
+
+	_, err = Project(row, []string{"started"}, fieldsSchema)
+	var qe *QueryError
+	if !errors.As(err, &qe) || qe.Params[0].Param != "fields" {
+		t.Errorf("Project() error = %v, want *QueryError for fields", err)
+	}
+}
+
+func TestPagebleRsMarshalJSON(t *testing.T) {
+	req := ParseQueryParams(map[string][]string{"fields": {"name, period_start"}})
+	q, err := BuildListQuery(req, "SELECT id, name, period_start FROM t", fieldsSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	rs, err := NewPage(req, q, []testRow{{ID: 1, Name: "Go", PeriodStart: "2019-01-01"}}, 1)
+	if err != nil {
+		t.Fatalf("NewPage() error = %v", err)
+	}
+	data, err := json.Marshal(rs)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"total":1,"content":[{"name":"Go","periodStart":"2019-01-01"}],"page":1,"size":10}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	// Без fields элементы отдаются целиком
+	rs.Fields = nil
+	data, _ = json.Marshal(rs)
+	want = `{"total":1,"content":[{"id":1,"name":"Go","periodStart":"2019-01-01"}],"page":1,"size":10}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestBuildListQueryUnknownFields(t *testing.T) {
+	_, err := BuildListQuery(PagebleRq{Fields: []string{"salary"}}, "SELECT * FROM t", fieldsSchema)
+	var qe *QueryError
+	if !errors.As(err, &qe) || len(qe.Params) != 1 || qe.Params[0].Param != "fields" {
+		t.Errorf("BuildListQuery() error = %v, want *QueryError for fields", err)
+	}
+}
@@ -0,0 +1,141 @@
+package entityreqdecorator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ParseInclude разбирает параметр include=technologies,technologies.tags: связи, которые
+// нужно подгрузить и встроить в ответ. Допустимые связи перечислены в allowed,
+// остальные возвращаются как *QueryError. Вложенная связь добавляет родительскую.
+// Возвращает nil, если параметр не передан.
+func ParseInclude(queryParams map[string][]string, allowed ...string) ([]string, error) {
+	values, ok := queryParams["include"]
+	if !ok || len(values) == 0 {
+		return nil, nil
+	}
+	errs := &QueryError{}
+	var include []string
+	for _, name := range strings.Split(values[0], ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			errs.add("include", "unknown relation %q", name)
+			continue
+		}
+		// technologies.tags подгружается вместе с technologies
+		for i := range name {
+			if name[i] == '.' {
+				include = append(include, name[:i])
+			}
+		}
+		include = append(include, name)
+	}
+	if err := errs.orNil(); err != nil {
+		return nil, err
+	}
+	return slices.Compact(slices.Sorted(slices.Values(include))), nil
+}
+
+// Expanded запись с подгруженными связями из include. В JSON связи добавляются
+// к полям записи, например {"id":1,"title":"Go","tags":[...]}.
+type Expanded[T any] struct {
+	Item      T
+	Relations map[string]interface{}
+}
+
+// MarshalJSON реализует json.Marshaler
+func (e Expanded[T]) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(e.Item)
+	if err != nil || len(e.Relations) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", e.Item, err)
+	}
+	for name, value := range e.Relations {
+		if fields[name], err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// relationNames возвращает имена подгруженных связей: Project оставляет их в ответе
+// независимо от fields
+func (e Expanded[T]) relationNames() []string {
+	names := make([]string, 0, len(e.Relations))
+	for name := range e.Relations {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Expand оборачивает записи в Expanded без связей
+func Expand[T any](items []T) []Expanded[T] {
+	expanded := make([]Expanded[T], len(items))
+	for i, item := range items {
+		expanded[i] = Expanded[T]{Item: item, Relations: map[string]interface{}{}}
+	}
+	return expanded
+}
+
+// WithContent возвращает страницу rs с другим содержимым, например с записями из Expand
+func WithContent[T, U any](rs PagebleRs[T], content []U) PagebleRs[U] {
+	return PagebleRs[U]{
+		Total:      rs.Total,
+		Content:    content,
+		Page:       rs.Page,
+		Size:       rs.Size,
+		Sort:       rs.Sort,
+		NextCursor: rs.NextCursor,
+		PrevCursor: rs.PrevCursor,
+		Fields:     rs.Fields,
+	}
+}
+
+// RelatedByOwner подгружает записи таблицы репозитория, связанные через rel с каждым
+// из ownerIDs, одним запросом. Записи каждого владельца идут в порядке id,
+// у владельцев без связей в результате нет ключа.
+func (r *Repository[T]) RelatedByOwner(rel Relation, ownerIDs []int64) (map[int64][]T, error) {
+	related := make(map[int64][]T)
+	if len(ownerIDs) == 0 {
+		return related, nil
+	}
+
+	columns := r.schema.Columns()
+	names := make([]string, len(columns))
+	for i, f := range columns {
+		names[i] = "t." + f.Name
+	}
+	query := "SELECT l." + rel.OwnerColumn + ", " + strings.Join(names, ", ") +
+		" FROM " + r.table + " t JOIN " + rel.Table + " l ON l." + rel.TargetColumn + " = t.id" +
+		" WHERE l." + rel.OwnerColumn + " = ANY($1)" +
+		" ORDER BY t.id"
+	rows, err := r.db.Query(query, pq.Array(ownerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s %s list: %w", rel.OwnerName, r.name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ownerID int64
+		var item T
+		dest := append([]interface{}{&ownerID}, ScanTargets(columns, r.columns(&item))...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", r.name, err)
+		}
+		related[ownerID] = append(related[ownerID], item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return related, nil
+}
@@ -0,0 +1,93 @@
+package entityreqdecorator
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseInclude(t *testing.T) {
+	allowed := []string{"technologies", "technologies.tags"}
+	tests := []struct {
+		name    string
+		query   map[string][]string
+		want    []string
+		wantErr bool
+	}{
+		{name: "без параметра", query: map[string][]string{}, want: nil},
+		{name: "одна связь", query: map[string][]string{"include": {"technologies"}}, want: []string{"technologies"}},
+		{
+			name:  "вложенная связь добавляет родительскую",
+			query: map[string][]string{"include": {" technologies.tags ,"}},
+			want:  []string{"technologies", "technologies.tags"},
+		},
+		{
+			name:  "повторы убираются",
+			query: map[string][]string{"include": {"technologies,technologies.tags,technologies"}},
+			want:  []string{"technologies", "technologies.tags"},
+		},
+		{name: "неизвестная связь", query: map[string][]string{"include": {"technologies,tags"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseInclude(tt.query, allowed...)
+			if tt.wantErr {
+				var qe *QueryError
+				if !errors.As(err, &qe) || qe.Params[0].Param != "include" {
+					t.Errorf("ParseInclude() error = %v, want *QueryError for include", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseInclude() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseInclude() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandedMarshalJSON(t *testing.T) {
+	items := Expand([]testRow{{ID: 1, Name: "Go", PeriodStart: "2019-01-01"}})
+
+	// Без связей JSON совпадает с самой записью
+	data, err := json.Marshal(items[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"id":1,"name":"Go","periodStart":"2019-01-01"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	tags := Expand([]testRow{{ID: 7, Name: "Backend"}})
+	items[0].Relations["tags"] = tags
+	tags[0].Relations["owners"] = []int{}
+	data, _ = json.Marshal(items[0])
+	want := `{"id":1,"name":"Go","periodStart":"2019-01-01","tags":[{"id":7,"name":"Backend","owners":[],"periodStart":""}]}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	// Связи остаются в ответе вместе с fields
+	projected, err := Project(items[0], []string{"name"}, fieldsSchema)
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+	data, _ = json.Marshal(projected)
+	if want := `{"name":"Go","tags":[{"id":7,"name":"Backend","owners":[],"periodStart":""}]}`; string(data) != want {
+		t.Errorf("Project() = %s, want %s", data, want)
+	}
+}
+
+func TestWithContent(t *testing.T) {
+	rs := PagebleRs[testRow]{Total: 3, Content: []testRow{{ID: 1}}, Page: 2, Size: 1, NextCursor: "n", Fields: []string{"id"}}
+	got := WithContent(rs, Expand(rs.Content))
+	if got.Total != 3 || got.Page != 2 || got.Size != 1 || got.NextCursor != "n" || !slices.Equal(got.Fields, rs.Fields) {
+		t.Errorf("WithContent() = %+v, want page fields of %+v", got, rs)
+	}
+	if len(got.Content) != 1 || got.Content[0].Item.ID != 1 {
+		t.Errorf("WithContent() content = %+v", got.Content)
+	}
+}
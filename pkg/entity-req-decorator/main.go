@@ -1,7 +1,5 @@
 package entityreqdecorator
 
-import "strings"
-
 type SortBy struct {
 	Field string `json:"field"`
 	Order string `json:"order"` // ASC, DESC
@@ -12,6 +10,18 @@ type PagebleRq struct {
 	Size   int
 	Sort   []SortBy 
 	Filter map[string]SQLGenerator
+	// Strict требует, чтобы все фильтры и сортировки относились к полям схемы
+	Strict bool
+	// CursorMode включает курсорную пагинацию вместо LIMIT/OFFSET; пустой Cursor — первая страница
+	CursorMode bool
+	Cursor     string
+	// WithTotal считать общее количество записей в режиме курсора
+	WithTotal bool
+	// Fields поля, которые нужно вернуть клиенту; пусто — все поля
+	Fields []string
+	// Where условие по нескольким полям из тела POST /search, применяется вместе с Filter.
+	// Поле задается в каждом предикате, группы anf/orf без поля объединяют разные поля.
+	Where SQLGenerator
 }
 
 type PagebleRs[T any] struct {
@@ -20,10 +30,17 @@ type PagebleRs[T any] struct {
 	Page    int      `json:"page,omitempty"`
 	Size    int      `json:"size,omitempty"`
 	Sort    []SortBy `json:"sort,omitempty"`
+	// NextCursor и PrevCursor курсоры соседних страниц в режиме курсора
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	// Fields поля элементов Content, которые попадают в JSON (см. MarshalJSON)
+	Fields []string `json:"-"`
 }
 
+// SQLGenerator предикат фильтра: хранит поле, значения и вложенные предикаты.
+// SQL по нему строит только QueryBuilder — с плейсхолдерами и проверкой по схеме.
 type SQLGenerator interface {
-	GenerateSQL() string
+	base() *Predicate
 }
 type FieldSetter interface {
 	SetField(field string)
@@ -55,13 +72,36 @@ func init() {
 		"ne": func(raw string) SQLGenerator {
 			return &PredicateNE{Predicate: Predicate{Value: raw}}
 		},
-		// "anf": func(raw string) SQLGenerator {
-		// 	return &PredicateANF{
-		// 		predicate: predicate{
-		// 			InnerPredicate: parseANF(raw),
-		// 		},
-		// 	}
-		// },
+		"in": func(raw string) SQLGenerator {
+			return &PredicateIN{Predicate: Predicate{Values: parseValueList(raw)}}
+		},
+		"nin": func(raw string) SQLGenerator {
+			return &PredicateNIN{Predicate: Predicate{Values: parseValueList(raw)}}
+		},
+		"anf": func(raw string) SQLGenerator {
+			return &PredicateANF{Predicate: Predicate{InnerPredicate: parseGroup(raw)}}
+		},
+		"orf": func(raw string) SQLGenerator {
+			return &PredicateORF{Predicate: Predicate{InnerPredicate: parseGroup(raw)}}
+		},
+		"isnull": func(raw string) SQLGenerator {
+			return &PredicateIsNull{}
+		},
+		"notnull": func(raw string) SQLGenerator {
+			return &PredicateNotNull{}
+		},
+		"between": func(raw string) SQLGenerator {
+			return &PredicateBetween{Predicate: Predicate{Values: parseValueList(raw)}}
+		},
+		"ilike": func(raw string) SQLGenerator {
+			return &PredicateILike{Predicate: Predicate{Value: raw}}
+		},
+		"startswith": func(raw string) SQLGenerator {
+			return &PredicateStartsWith{Predicate: Predicate{Value: raw}}
+		},
+		"endswith": func(raw string) SQLGenerator {
+			return &PredicateEndsWith{Predicate: Predicate{Value: raw}}
+		},
 	}
 }
 
@@ -79,130 +119,100 @@ var predicatByStruct = map[string]func(raw string) SQLGenerator{
 }
 
 type Predicate struct {
-	Field          string
-	Value          string
+	Field string
+	Value string
+	// Values значения списка для in/nin и границы для between
+	Values         []string
 	InnerPredicate []SQLGenerator
 }
-type PredicateLike struct {
-	Predicate
+
+// base возвращает общую часть предиката; доступен у всех предикатов через встраивание
+func (p *Predicate) base() *Predicate {
+	return p
 }
 
-func (p *PredicateLike) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
+// SetField задает поле предиката и всех вложенных предикатов
+func (p *Predicate) SetField(field string) {
+	p.Field = field
+	for _, inner := range p.InnerPredicate {
+		if setter, ok := inner.(FieldSetter); ok {
+			setter.SetField(field)
+		}
 	}
-	return p.Field + " LIKE " + p.Value
 }
-
-type PredicateEQ struct {
+type PredicateLike struct {
 	Predicate
 }
 
-func (p *PredicateEQ) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
-	}
-	return p.Field + " = " + p.Value
+type PredicateEQ struct {
+	Predicate
 }
 
 type PredicateGT struct {
 	Predicate
 }
 
-func (p *PredicateGT) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
-	}
-	return p.Field + " > " + p.Value
-}
-
-func (p *PredicateGT) SetField(field string) {
-	p.Field = field
-}
-
 type PredicateLT struct {
 	Predicate
 }
 
-func (p *PredicateLT) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
-	}
-	return p.Field + " < " + p.Value
-}
-
-func (p *PredicateLT) SetField(field string) {
-	p.Field = field
-}
-
 type PredicateGTE struct {
 	Predicate
 }
 
-func (p *PredicateGTE) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
-	}
-	return p.Field + " >= " + p.Value
+type PredicateLTE struct {
+	Predicate
 }
 
-func (p *PredicateGTE) SetField(field string) {
-	p.Field = field
+type PredicateNE struct {
+	Predicate
 }
 
-type PredicateLTE struct {
+type PredicateANF struct {
 	Predicate
 }
 
-func (p *PredicateLTE) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
-	}
-	return p.Field + " <= " + p.Value
+// PredicateORF объединяет вложенные предикаты через OR: orf(lt(2015),eq(2017))
+type PredicateORF struct {
+	Predicate
 }
 
-func (p *PredicateLTE) SetField(field string) {
-	p.Field = field
+// PredicateIN проверяет вхождение в список: in(a,b,c)
+type PredicateIN struct {
+	Predicate
 }
 
-type PredicateNE struct {
+// PredicateNIN проверяет отсутствие в списке: nin(a,b,c)
+type PredicateNIN struct {
 	Predicate
 }
 
-func (p *PredicateNE) GenerateSQL() string {
-	if p.Value == "" {
-		return ""
-	}
-	return p.Field + " != " + p.Value
+// PredicateIsNull проверяет отсутствие значения: isnull()
+type PredicateIsNull struct {
+	Predicate
 }
 
-func (p *PredicateNE) SetField(field string) {
-	p.Field = field
+// PredicateNotNull проверяет наличие значения: notnull()
+type PredicateNotNull struct {
+	Predicate
 }
 
-type PredicateANF struct {
+// PredicateBetween проверяет попадание в диапазон включительно: between(a,b)
+type PredicateBetween struct {
 	Predicate
 }
 
-func (p *PredicateANF) GenerateSQL() string {
-	if len(p.InnerPredicate) == 0 {
-		return ""
-	}
-
-	var conditions []string
-	for _, pred := range p.InnerPredicate {
-		if sql := pred.GenerateSQL(); sql != "" {
-			conditions = append(conditions, sql)
-		}
-	}
-
-	if len(conditions) == 0 {
-		return ""
-	}
+// PredicateILike ищет подстроку без учета регистра: ilike(go)
+type PredicateILike struct {
+	Predicate
+}
 
-	return "(" + strings.Join(conditions, " AND ") + ")"
+// PredicateStartsWith проверяет начало строки без учета регистра: startswith(go)
+type PredicateStartsWith struct {
+	Predicate
 }
 
-func (p *PredicateANF) SetField(field string) {
-	p.Field = field
+// PredicateEndsWith проверяет конец строки без учета регистра: endswith(lang)
+type PredicateEndsWith struct {
+	Predicate
 }
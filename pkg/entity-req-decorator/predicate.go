@@ -1,6 +1,8 @@
 package entityreqdecorator
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -10,57 +12,175 @@ const (
 	SIZE = 10
 )
 
+// reservedParams параметры запроса, которые не являются фильтрами
+var reservedParams = map[string]bool{
+	"page":      true,
+	"size":      true,
+	"sort":      true,
+	"cursor":    true,
+	"withTotal": true,
+	"fields":    true,
+	"include":   true,
+}
+
+// ParseQueryParams разбирает параметры списочного запроса в нестрогом режиме:
+// неверные page, size и sort заменяются значениями по умолчанию или пропускаются,
+// фильтры по неизвестным полям игнорируются. Используется админкой, где в адресе
+// страницы есть и другие параметры.
 func ParseQueryParams(queryParams map[string][]string) PagebleRq {
+	req, _ := parseQueryParams(queryParams, false)
+	return req
+}
+
+// ParseQueryParamsStrict разбирает параметры списочного запроса в строгом режиме.
+// Неверные page, size и sort, а также неизвестные операторы возвращаются в *QueryError;
+// в полученном PagebleRq выставлен Strict, поэтому BuildListQuery дополнительно
+// сообщает о фильтрах и сортировках по полям, которых нет в схеме.
+func ParseQueryParamsStrict(queryParams map[string][]string) (PagebleRq, error) {
+	req, errs := parseQueryParams(queryParams, true)
+	return req, errs.orNil()
+}
+
+func parseQueryParams(queryParams map[string][]string, strict bool) (PagebleRq, *QueryError) {
 	req := PagebleRq{
 		Page:   PAGE,
 		Size:   SIZE,
 		Sort:   []SortBy{},
 		Filter: make(map[string]SQLGenerator),
+		Strict: strict,
 	}
+	errs := &QueryError{}
 
 	// Парсинг пагинации
 	if pageStr, ok := queryParams["page"]; ok && len(pageStr) > 0 {
 		if page, err := strconv.Atoi(pageStr[0]); err == nil && page > 0 {
 			req.Page = page
+		} else {
+			errs.add("page", "must be a positive integer")
 		}
 	}
 
 	if sizeStr, ok := queryParams["size"]; ok && len(sizeStr) > 0 {
 		if size, err := strconv.Atoi(sizeStr[0]); err == nil && size > 0 {
 			req.Size = size
+		} else {
+			errs.add("size", "must be a positive integer")
+		}
+	}
+
+	req.Fields = ParseFields(queryParams)
+
+	if cursor, ok := queryParams["cursor"]; ok {
+		req.CursorMode = true
+		if len(cursor) > 0 {
+			req.Cursor = cursor[0]
+		}
+		if _, ok := queryParams["page"]; ok {
+			errs.add("page", "cannot be combined with cursor")
+		}
+	}
+
+	if withTotal, ok := queryParams["withTotal"]; ok && len(withTotal) > 0 {
+		if v, err := strconv.ParseBool(withTotal[0]); err == nil {
+			req.WithTotal = v
+		} else {
+			errs.add("withTotal", "must be true or false")
 		}
 	}
 
 	if sortParams, ok := queryParams["sort"]; ok {
 		for _, sortParam := range sortParams {
-			if parts := strings.Split(sortParam, ","); len(parts) == 2 {
-				field := strings.TrimSpace(parts[0])
-				order := strings.TrimSpace(strings.ToUpper(parts[1]))
-				if order == "ASC" || order == "DESC" {
-					req.Sort = append(req.Sort, SortBy{
-						Field: field,
-						Order: order,
-					})
-				}
+			parts := strings.Split(sortParam, ",")
+			if len(parts) != 2 {
+				errs.add("sort", "%q must be in the form field,ASC or field,DESC", sortParam)
+				continue
+			}
+			field := strings.TrimSpace(parts[0])
+			order := strings.TrimSpace(strings.ToUpper(parts[1]))
+			if field == "" || (order != "ASC" && order != "DESC") {
+				errs.add("sort", "%q must be in the form field,ASC or field,DESC", sortParam)
+				continue
 			}
+			req.Sort = append(req.Sort, SortBy{
+				Field: field,
+				Order: order,
+			})
 		}
 	}
 
 	// Парсинг фильтров
 	for key, values := range queryParams {
 		// Пропускаем уже обработанные параметры
-		if key == "page" || key == "size" || key == "sort" {
+		if reservedParams[key] {
 			continue
 		}
 
 		if len(values) > 0 {
+			if message, ok := invalidOperator(values[0]); ok && strict {
+				errs.add(key, "%s", message)
+				continue
+			}
 			req.Filter[key] = parsePredicate(key, values[0])
 		}
 	}
 
-	return req
+	sort.SliceStable(errs.Params, func(i, j int) bool {
+		return errs.Params[i].Param < errs.Params[j].Param
+	})
+	return req, errs
 }
 
+// unknownOperator проверяет, что значение записано как вызов оператора "op(...)",
+// которого нет в predicatByStruct. В нестрогом режиме такое значение сравнивается на равенство.
+func unknownOperator(value string) (string, bool) {
+	open := strings.Index(value, "(")
+	if open <= 0 || !strings.HasSuffix(value, ")") {
+		return "", false
+	}
+	operator := value[:open]
+	for _, r := range operator {
+		if r < 'a' || r > 'z' {
+			return "", false
+		}
+	}
+	if _, ok := predicatByStruct[operator]; ok {
+		return "", false
+	}
+	return operator, true
+}
+
+// invalidOperator проверяет значение фильтра для строгого режима: неизвестный оператор
+// на верхнем уровне или внутри anf/orf и аргументы групп, которые не являются вызовом
+// оператора. В нестрогом режиме такие аргументы групп пропускаются (см. parseGroup).
+func invalidOperator(value string) (string, bool) {
+	if operator, ok := unknownOperator(value); ok {
+		return "unknown operator " + operator, true
+	}
+	group, inner := extractOperator(value)
+	if (group != "anf" && group != "orf") || !strings.HasSuffix(value, ")") {
+		return "", false
+	}
+	for _, part := range splitByCommaOutsideParens(inner) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if parseOperator(part) == nil {
+			if operator, ok := unknownOperator(part); ok {
+				return "unknown operator " + operator, true
+			}
+			return fmt.Sprintf("%q in %s() must be an operator call", part, group), true
+		}
+		if message, ok := invalidOperator(part); ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// parsePredicate разбирает значение фильтра: "value", "gt(10)", "in(1,2,3)",
+// "anf(gte(2019),orf(lt(2015),eq(2017)))". Значение с неизвестным оператором
+// считается обычным значением для сравнения на равенство.
 func parsePredicate(field, value string) SQLGenerator {
 	if value == "" {
 		return &PredicateEQ{
@@ -71,74 +191,36 @@ func parsePredicate(field, value string) SQLGenerator {
 		}
 	}
 
-	if !strings.Contains(value, "(") || !strings.HasSuffix(value, ")") {
+	predicate := parseOperator(value)
+	if predicate == nil {
 		return &PredicateEQ{
 			Predicate: Predicate{
 				Field: field,
-				Value: value,
+				Value: value, // сохраняем оригинальное значение
 			},
 		}
 	}
 
-	operator, innerValue := extractOperator(value)
+	if setter, ok := predicate.(FieldSetter); ok {
+		setter.SetField(field)
+	}
+	return predicate
+}
 
-	switch operator {
-	// TODO or predicate
-	case "like":
-		return &PredicateLike{Predicate: Predicate{Value: innerValue, Field: field}}
-	case "anf":
-		// anf(gt(20),lt(30)) - AND
-		predicates := parseANF(innerValue, field)
-		return &PredicateANF{
-			Predicate: Predicate{
-				Field:          field,
-				InnerPredicate: predicates,
-			},
-		}
-	case "gt":
-		return &PredicateGT{
-			Predicate: Predicate{
-				Field: field,
-				Value: innerValue,
-			},
-		}
-	case "lt":
-		return &PredicateLT{
-			Predicate: Predicate{
-				Field: field,
-				Value: innerValue,
-			},
-		}
-	case "gte":
-		return &PredicateGTE{
-			Predicate: Predicate{
-				Field: field,
-				Value: innerValue,
-			},
-		}
-	case "lte":
-		return &PredicateLTE{
-			Predicate: Predicate{
-				Field: field,
-				Value: innerValue,
-			},
-		}
-	case "ne":
-		return &PredicateNE{
-			Predicate: Predicate{
-				Field: field,
-				Value: innerValue,
-			},
-		}
-	default:
-		return &PredicateEQ{
-			Predicate: Predicate{
-				Field: field,
-				Value: value, // сохраняем оригинальное значение
-			},
-		}
+// parseOperator разбирает выражение вида "op(inner)" по таблице predicatByStruct.
+// Возвращает nil, если выражение не является вызовом известного оператора.
+func parseOperator(s string) SQLGenerator {
+	if !strings.Contains(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil
 	}
+	operator, innerValue := extractOperator(s)
+	constructor, ok := predicatByStruct[operator]
+	if !ok || operator == "" {
+		return nil
+	}
+	return constructor(innerValue)
 }
+
 func extractOperator(s string) (string, string) {
 	openParen := strings.Index(s, "(")
 	if openParen == -1 {
@@ -150,7 +232,11 @@ func extractOperator(s string) (string, string) {
 
 	return operator, inner
 }
-func parseANF(s, field string) []SQLGenerator {
+
+// parseGroup разбирает аргументы anf/orf: gt(20),orf(lt(10),eq(15)).
+// Аргументы с неизвестными операторами пропускаются; в строгом режиме о них
+// заранее сообщает invalidOperator.
+func parseGroup(s string) []SQLGenerator {
 	var predicates []SQLGenerator
 	parts := splitByCommaOutsideParens(s)
 
@@ -159,53 +245,25 @@ func parseANF(s, field string) []SQLGenerator {
 		if part == "" {
 			continue
 		}
-
-		operator, innerValue := extractOperator(part)
-
-		switch operator {
-		case "gt":
-			predicates = append(predicates, &PredicateGT{
-				Predicate: Predicate{
-					Value: innerValue,
-					Field: field,
-				},
-			})
-		case "lt":
-			predicates = append(predicates, &PredicateLT{
-				Predicate: Predicate{
-					Value: innerValue, Field: field,
-				},
-			})
-		case "gte":
-			predicates = append(predicates, &PredicateGTE{
-				Predicate: Predicate{
-					Value: innerValue, Field: field,
-				},
-			})
-		case "lte":
-			predicates = append(predicates, &PredicateLTE{
-				Predicate: Predicate{
-					Value: innerValue, Field: field,
-				},
-			})
-		case "eq":
-			predicates = append(predicates, &PredicateEQ{
-				Predicate: Predicate{
-					Value: innerValue, Field: field,
-				},
-			})
-		case "like":
-			predicates = append(predicates, &PredicateLike{
-				Predicate: Predicate{
-					Value: innerValue, Field: field,
-				},
-			})
+		if predicate := parseOperator(part); predicate != nil {
+			predicates = append(predicates, predicate)
 		}
 	}
 
 	return predicates
 }
 
+// parseValueList разбирает аргументы in/nin: a,b,c
+func parseValueList(s string) []string {
+	var values []string
+	for _, part := range splitByCommaOutsideParens(s) {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
 func splitByCommaOutsideParens(s string) []string {
 	var result []string
 	var current strings.Builder
@@ -1,6 +1,7 @@
 package entityreqdecorator
 
 import (
+	"reflect"
 	"slices"
 	"testing"
 )
@@ -133,6 +134,113 @@ func TestParseQueryParams(t *testing.T) {
 					},
 				},
 			},
+		},
+		{
+			name: "or parameters",
+			queryParams: map[string][]string{
+				"year": {"orf(lt(2015),eq(2017))"},
+			},
+			want: PagebleRq{
+				Size: SIZE,
+				Page: PAGE,
+				Filter: map[string]SQLGenerator{
+					"year": &PredicateORF{
+						Predicate: Predicate{
+							InnerPredicate: []SQLGenerator{
+								&PredicateLT{Predicate: Predicate{Value: "2015", Field: "year"}},
+								&PredicateEQ{Predicate: Predicate{Value: "2017", Field: "year"}},
+							},
+							Field: "year",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "nested parameters",
+			queryParams: map[string][]string{
+				"year": {"anf(gte(2019),orf(lt(2015),eq(2017)))"},
+			},
+			want: PagebleRq{
+				Size: SIZE,
+				Page: PAGE,
+				Filter: map[string]SQLGenerator{
+					"year": &PredicateANF{
+						Predicate: Predicate{
+							InnerPredicate: []SQLGenerator{
+								&PredicateGTE{Predicate: Predicate{Value: "2019", Field: "year"}},
+								&PredicateORF{
+									Predicate: Predicate{
+										InnerPredicate: []SQLGenerator{
+											&PredicateLT{Predicate: Predicate{Value: "2015", Field: "year"}},
+											&PredicateEQ{Predicate: Predicate{Value: "2017", Field: "year"}},
+										},
+										Field: "year",
+									},
+								},
+							},
+							Field: "year",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "in and nin parameters",
+			queryParams: map[string][]string{
+				"id":     {"in(1, 2,3)"},
+				"status": {"nin(new,spam)"},
+			},
+			want: PagebleRq{
+				Size: SIZE,
+				Page: PAGE,
+				Filter: map[string]SQLGenerator{
+					"id": &PredicateIN{
+						Predicate: Predicate{Values: []string{"1", "2", "3"}, Field: "id"},
+					},
+					"status": &PredicateNIN{
+						Predicate: Predicate{Values: []string{"new", "spam"}, Field: "status"},
+					},
+				},
+			},
+		},
+		{
+			name: "null and range parameters",
+			queryParams: map[string][]string{
+				"period_end":   {"isnull()"},
+				"period_start": {"between(2019-01-01,2020-01-01)"},
+				"name":         {"startswith(Go)"},
+			},
+			want: PagebleRq{
+				Size: SIZE,
+				Page: PAGE,
+				Filter: map[string]SQLGenerator{
+					"period_end": &PredicateIsNull{
+						Predicate: Predicate{Field: "period_end"},
+					},
+					"period_start": &PredicateBetween{
+						Predicate: Predicate{Values: []string{"2019-01-01", "2020-01-01"}, Field: "period_start"},
+					},
+					"name": &PredicateStartsWith{
+						Predicate: Predicate{Value: "Go", Field: "name"},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown operator is kept as value",
+			queryParams: map[string][]string{
+				"name": {"foo(bar)"},
+			},
+			want: PagebleRq{
+				Size: SIZE,
+				Page: PAGE,
+				Filter: map[string]SQLGenerator{
+					"name": &PredicateEQ{
+						Predicate: Predicate{Value: "foo(bar)", Field: "name"},
+					},
+				},
+			},
 		}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -168,15 +276,114 @@ func TestParseQueryParams(t *testing.T) {
 							got.Filter,
 						)
 					}
-					if gotFilter.GenerateSQL() != wantFilter.GenerateSQL() {
-						t.Errorf(
-							"gotFilter %v with gotFilter.GenerateSQL() %s not equal wantFilter %v with wantFilter.GenerateSQL() %s",
-							gotFilter, gotFilter.GenerateSQL(),
-							wantFilter, wantFilter.GenerateSQL(),
-						)
+					if !reflect.DeepEqual(gotFilter, wantFilter) {
+						t.Errorf("gotFilter %+v not equal wantFilter %+v", gotFilter, wantFilter)
 					}
 				}
 			}
 		})
 	}
 }
+
+func TestParseQueryParamsStrict(t *testing.T) {
+	tests := []struct {
+		name        string
+		queryParams map[string][]string
+		wantParams  []string
+	}{
+		{
+			name: "valid parameters",
+			queryParams: map[string][]string{
+				"page": {"2"},
+				"size": {"20"},
+				"sort": {"title,asc"},
+				"name": {"ilike(go)"},
+			},
+		},
+		{
+			name: "invalid pagination",
+			queryParams: map[string][]string{
+				"page": {"0"},
+				"size": {"ten"},
+			},
+			wantParams: []string{"page", "size"},
+		},
+		{
+			name: "malformed sort",
+			queryParams: map[string][]string{
+				"sort": {"title", "id,up"},
+			},
+			wantParams: []string{"sort", "sort"},
+		},
+		{
+			name: "unknown operator",
+			queryParams: map[string][]string{
+				"name": {"lik(go)"},
+			},
+			wantParams: []string{"name"},
+		},
+		{
+			name: "unknown operator inside group",
+			queryParams: map[string][]string{
+				"year": {"anf(gte(2015),orf(lt(2010),eqq(2017)))"},
+			},
+			wantParams: []string{"year"},
+		},
+		{
+			name: "group argument without operator",
+			queryParams: map[string][]string{
+				"year": {"orf(2015,gt(2020))"},
+			},
+			wantParams: []string{"year"},
+		},
+		{
+			name: "valid nested group",
+			queryParams: map[string][]string{
+				"year": {"anf(gte(2015),orf(lt(2010),in(2017,2018)))"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseQueryParamsStrict(tt.queryParams)
+			if !req.Strict {
+				t.Errorf("ParseQueryParamsStrict() Strict = false, want true")
+			}
+			if len(tt.wantParams) == 0 {
+				if err != nil {
+					t.Fatalf("ParseQueryParamsStrict() error = %v", err)
+				}
+				return
+			}
+			qe, ok := err.(*QueryError)
+			if !ok {
+				t.Fatalf("ParseQueryParamsStrict() error = %v, want *QueryError", err)
+			}
+			var got []string
+			for _, p := range qe.Params {
+				got = append(got, p.Param)
+			}
+			if !slices.Equal(got, tt.wantParams) {
+				t.Errorf("QueryError params = %v, want %v", got, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestParseQueryParamsLenient(t *testing.T) {
+	req := ParseQueryParams(map[string][]string{
+		"page": {"0"},
+		"sort": {"title"},
+		"name": {"lik(go)"},
+	})
+	if req.Strict {
+		t.Errorf("ParseQueryParams() Strict = true, want false")
+	}
+	if req.Page != PAGE || len(req.Sort) != 0 {
+		t.Errorf("ParseQueryParams() Page = %d, Sort = %v, want defaults", req.Page, req.Sort)
+	}
+	want := &PredicateEQ{Predicate: Predicate{Field: "name", Value: "lik(go)"}}
+	if got := req.Filter["name"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseQueryParams() name filter = %+v, want equality with raw value", got)
+	}
+}
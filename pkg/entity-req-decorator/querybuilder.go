@@ -1,7 +1,10 @@
 package entityreqdecorator
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -25,116 +28,290 @@ func NewQueryBuilder() *QueryBuilder {
 // AddFilter добавляет условие фильтрации в WHERE часть запроса.
 // field: имя поля таблицы
 // predicate: предикат (например, PredicateEQ, PredicateGT и т.д.)
-// fieldValidator: функция валидации имени поля (возвращает true, если поле допустимо)
+// schema: описание полей сущности; значения приводятся к типу поля перед передачей в запрос
 // Пример для predicate типа PredicateEQ{Value: "test"}:
 //   Добавляет условие: "name = $1"
 //   Добавляет параметр: "test" в qb.Params
 //   Увеличивает paramCounter: с 1 на 2
-func (qb *QueryBuilder) AddFilter(field string, predicate SQLGenerator, fieldValidator func(string) bool) {
-	if !fieldValidator(field) {
-		return
+// Поля, которых нет в схеме, пропускаются. Для нефильтруемого поля, запрещенного
+// оператора или значения не того типа возвращается *QueryError; условие при этом не добавляется.
+func (qb *QueryBuilder) AddFilter(field string, predicate SQLGenerator, schema Schema) error {
+	f, ok := schema.Lookup(field)
+	if !ok {
+		return nil
 	}
+	if !f.Filterable {
+		errs := &QueryError{}
+		errs.add(field, "filtering by this field is not allowed")
+		return errs
+	}
+
+	// Условие собирается на копии, чтобы ошибка не оставила в запросе лишних параметров
+	params, counter := qb.Params, qb.paramCounter
+	condition, err := qb.fieldCondition(f, predicate)
+	if err != nil {
+		qb.Params, qb.paramCounter = params, counter
+		errs := &QueryError{}
+		errs.add(field, "%s", err)
+		return errs
+	}
+	if condition != "" {
+		qb.WhereConditions = append(qb.WhereConditions, condition)
+	}
+	return nil
+}
 
-	sqlPart := predicate.GenerateSQL()
-	if sqlPart == "" {
-		return
+// AddWhere добавляет условие из дерева предикатов по разным полям (PagebleRq.Where).
+// Группы anf/orf без поля объединяют условия по разным полям, остальные предикаты
+// строятся по своему полю так же, как в AddFilter:
+// and(year >= 2015, or(name ILIKE 'go', id = 7)) дает "(year >= $1 AND (name ILIKE $2 OR id = $3))".
+// Ошибки всех предикатов собираются в один *QueryError по именам полей. Поля не из схемы
+// в strict-режиме считаются ошибкой, иначе пропускаются.
+func (qb *QueryBuilder) AddWhere(predicate SQLGenerator, schema Schema, strict bool) error {
+	params, counter := qb.Params, qb.paramCounter
+	errs := &QueryError{}
+	condition := qb.where(predicate, schema, strict, errs)
+	if err := errs.orNil(); err != nil {
+		qb.Params, qb.paramCounter = params, counter
+		return err
+	}
+	if condition != "" {
+		qb.WhereConditions = append(qb.WhereConditions, condition)
 	}
+	return nil
+}
 
+// where рекурсивно строит условие для AddWhere
+func (qb *QueryBuilder) where(predicate SQLGenerator, schema Schema, strict bool, errs *QueryError) string {
+	var sep string
+	var inner []SQLGenerator
 	switch p := predicate.(type) {
-	case *PredicateEQ:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s = $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, p.Value)
-		qb.paramCounter++
+	case *PredicateANF:
+		sep, inner = " AND ", p.InnerPredicate
+	case *PredicateORF:
+		sep, inner = " OR ", p.InnerPredicate
+	}
+	b, ok := predicate.(interface{ base() *Predicate })
+	if !ok {
+		errs.add("filter", "unsupported filter operator")
+		return ""
+	}
+	field := b.base().Field
+
+	// Группа без поля объединяет условия по разным полям
+	if sep != "" && field == "" {
+		var conditions []string
+		for _, p := range inner {
+			if condition := qb.where(p, schema, strict, errs); condition != "" {
+				conditions = append(conditions, condition)
+			}
+		}
+		if len(conditions) == 0 {
+			return ""
+		}
+		return "(" + strings.Join(conditions, sep) + ")"
+	}
 
-	case *PredicateLike:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s LIKE $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, "%"+p.Value+"%")
-		qb.paramCounter++
+	f, ok := schema.Lookup(field)
+	if !ok {
+		if strict {
+			errs.add(field, "unknown field")
+		}
+		return ""
+	}
+	if !f.Filterable {
+		errs.add(field, "filtering by this field is not allowed")
+		return ""
+	}
+	condition, err := qb.fieldCondition(f, predicate)
+	if err != nil {
+		errs.add(field, "%s", err)
+		return ""
+	}
+	return condition
+}
 
-	case *PredicateGT:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s > $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, p.Value)
-		qb.paramCounter++
+// fieldCondition строит условие по полю. Для поля-связи условие строится по колонке
+// связанной таблицы и оборачивается в EXISTS по таблице связей (см. RelationFilter).
+func (qb *QueryBuilder) fieldCondition(f Field, predicate SQLGenerator) (string, error) {
+	rel := f.Relation
+	if rel == nil {
+		return qb.condition(f, predicate)
+	}
+	target := f
+	target.Name = "t." + rel.Column
+	condition, err := qb.condition(target, predicate)
+	if err != nil || condition == "" {
+		return condition, err
+	}
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s l JOIN %s t ON t.id = l.%s WHERE l.%s = %s.id AND %s)",
+		rel.Table, rel.TargetTable, rel.TargetColumn, rel.OwnerColumn, rel.OwnerTable, condition), nil
+}
 
-	case *PredicateLT:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s < $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, p.Value)
-		qb.paramCounter++
+// condition строит параметризованное условие для предиката.
+// Группы anf/orf обходятся рекурсивно и заключаются в скобки, например
+// anf(gte(2019),orf(lt(2015),eq(2017))) дает "(year >= $1 AND (year < $2 OR year = $3))".
+// Возвращает пустую строку, если у предиката нет значения.
+func (qb *QueryBuilder) condition(f Field, predicate SQLGenerator) (string, error) {
+	operator := operatorName(predicate)
+	if operator == "" {
+		return "", fmt.Errorf("unsupported filter operator")
+	}
+	if !f.Allows(operator) {
+		return "", fmt.Errorf("operator %s is not allowed for this field", operator)
+	}
 
+	switch p := predicate.(type) {
+	case *PredicateEQ:
+		return qb.compare(f, "=", p.Value)
+	case *PredicateLike:
+		return qb.pattern(f, "LIKE", "%", p.Value, "%"), nil
+	case *PredicateILike:
+		return qb.pattern(f, "ILIKE", "%", p.Value, "%"), nil
+	case *PredicateStartsWith:
+		return qb.pattern(f, "ILIKE", "", p.Value, "%"), nil
+	case *PredicateEndsWith:
+		return qb.pattern(f, "ILIKE", "%", p.Value, ""), nil
+	case *PredicateGT:
+		return qb.compare(f, ">", p.Value)
+	case *PredicateLT:
+		return qb.compare(f, "<", p.Value)
 	case *PredicateGTE:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s >= $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, p.Value)
-		qb.paramCounter++
-
+		return qb.compare(f, ">=", p.Value)
 	case *PredicateLTE:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s <= $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, p.Value)
-		qb.paramCounter++
-
+		return qb.compare(f, "<=", p.Value)
 	case *PredicateNE:
-		qb.WhereConditions = append(qb.WhereConditions, fmt.Sprintf("%s != $%d", field, qb.paramCounter))
-		qb.Params = append(qb.Params, p.Value)
-		qb.paramCounter++
-
+		return qb.compare(f, "!=", p.Value)
+	case *PredicateIsNull:
+		return f.Name + " IS NULL", nil
+	case *PredicateNotNull:
+		return f.Name + " IS NOT NULL", nil
+	case *PredicateBetween:
+		if len(p.Values) != 2 {
+			return "", fmt.Errorf("between expects two values, got %d", len(p.Values))
+		}
+		from, err := f.Parse(p.Values[0])
+		if err != nil {
+			return "", err
+		}
+		to, err := f.Parse(p.Values[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s BETWEEN %s AND %s", f.Name, qb.bind(from), qb.bind(to)), nil
+	case *PredicateIN:
+		return qb.list(f, "IN", p.Values)
+	case *PredicateNIN:
+		return qb.list(f, "NOT IN", p.Values)
 	case *PredicateANF:
-		var anfConditions []string
-		for _, innerPred := range p.InnerPredicate {
-			switch ip := innerPred.(type) {
-			case *PredicateGT:
-				anfConditions = append(anfConditions, fmt.Sprintf("%s > $%d", field, qb.paramCounter))
-				qb.Params = append(qb.Params, ip.Value)
-				qb.paramCounter++
-
-			case *PredicateLT:
-				anfConditions = append(anfConditions, fmt.Sprintf("%s < $%d", field, qb.paramCounter))
-				qb.Params = append(qb.Params, ip.Value)
-				qb.paramCounter++
-
-			case *PredicateGTE:
-				anfConditions = append(anfConditions, fmt.Sprintf("%s >= $%d", field, qb.paramCounter))
-				qb.Params = append(qb.Params, ip.Value)
-				qb.paramCounter++
-
-			case *PredicateLTE:
-				anfConditions = append(anfConditions, fmt.Sprintf("%s <= $%d", field, qb.paramCounter))
-				qb.Params = append(qb.Params, ip.Value)
-				qb.paramCounter++
-
-			case *PredicateEQ:
-				anfConditions = append(anfConditions, fmt.Sprintf("%s = $%d", field, qb.paramCounter))
-				qb.Params = append(qb.Params, ip.Value)
-				qb.paramCounter++
-
-			case *PredicateLike:
-				anfConditions = append(anfConditions, fmt.Sprintf("%s LIKE $%d", field, qb.paramCounter))
-				qb.Params = append(qb.Params, "%"+ip.Value+"%")
-				qb.paramCounter++
-			}
+		return qb.group(f, " AND ", p.InnerPredicate)
+	case *PredicateORF:
+		return qb.group(f, " OR ", p.InnerPredicate)
+	}
+	return "", nil
+}
+
+// bind добавляет параметр и возвращает его плейсхолдер "$n"
+func (qb *QueryBuilder) bind(value interface{}) string {
+	placeholder := fmt.Sprintf("$%d", qb.paramCounter)
+	qb.Params = append(qb.Params, value)
+	qb.paramCounter++
+	return placeholder
+}
+
+// compare приводит значение к типу поля и возвращает условие вида "field op $n"
+func (qb *QueryBuilder) compare(f Field, op, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	v, err := f.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if f.ignoresCase() {
+		return fmt.Sprintf("lower(%s) %s lower(%s)", f.Name, op, qb.bind(v)), nil
+	}
+	return fmt.Sprintf("%s %s %s", f.Name, op, qb.bind(v)), nil
+}
+
+// pattern добавляет параметр-шаблон для LIKE/ILIKE. Спецсимволы в значении
+// экранируются, подстановочные знаки берутся только из prefix и suffix.
+func (qb *QueryBuilder) pattern(f Field, op, prefix, value, suffix string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s %s", f.Name, op, qb.bind(prefix+escapeLike(value)+suffix))
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE, чтобы % и _ из запроса
+// пользователя сравнивались как обычные символы. В Postgres символ экранирования
+// по умолчанию — обратная косая черта.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// list приводит значения списка к типу поля и возвращает условие вида "field IN ($n, $m)"
+func (qb *QueryBuilder) list(f Field, op string, values []string) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		v, err := f.Parse(value)
+		if err != nil {
+			return "", err
 		}
+		placeholders[i] = qb.bind(v)
+		if f.ignoresCase() {
+			placeholders[i] = "lower(" + placeholders[i] + ")"
+		}
+	}
+	if f.ignoresCase() {
+		return fmt.Sprintf("lower(%s) %s (%s)", f.Name, op, strings.Join(placeholders, ", ")), nil
+	}
+	return fmt.Sprintf("%s %s (%s)", f.Name, op, strings.Join(placeholders, ", ")), nil
+}
 
-		if len(anfConditions) > 0 {
-			qb.WhereConditions = append(qb.WhereConditions, "("+strings.Join(anfConditions, " AND ")+")")
+// group объединяет условия вложенных предикатов через sep и заключает их в скобки
+func (qb *QueryBuilder) group(f Field, sep string, predicates []SQLGenerator) (string, error) {
+	var conditions []string
+	for _, inner := range predicates {
+		condition, err := qb.condition(f, inner)
+		if err != nil {
+			return "", err
+		}
+		if condition != "" {
+			conditions = append(conditions, condition)
 		}
 	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, sep) + ")", nil
 }
 
 // AddSort добавляет сортировку в запрос на основе массива SortBy.
 // sorts: массив структур SortBy с полем Field и порядком Order (ASC/DESC)
-// fieldValidator: функция валидации имени поля
-// Пример для sorts = []SortBy{{Field: "name", Order: "ASC"}, {Field: "id", Order: "DESC"}}:
-//   Устанавливает qb.OrderByClause = " ORDER BY name ASC, id DESC"
-// Возвращаемое значение: void
-func (qb *QueryBuilder) AddSort(sorts []SortBy, fieldValidator func(string) bool) {
+// schema: описание полей сущности; поля без Sortable пропускаются
+// Пример для sorts = []SortBy{{Field: "name", Order: "ASC"}, {Field: "periodStart", Order: "DESC"}}:
+//   Устанавливает qb.OrderByClause = " ORDER BY name ASC, period_start DESC"
+// Возвращает примененные сортировки с публичными именами полей
+func (qb *QueryBuilder) AddSort(sorts []SortBy, schema Schema) []SortBy {
 	var orderBy []string
+	var applied []SortBy
 	for _, sort := range sorts {
-		if fieldValidator(sort.Field) {
-			orderBy = append(orderBy, fmt.Sprintf("%s %s", sort.Field, sort.Order))
+		if f, ok := schema.Lookup(sort.Field); ok && f.Sortable {
+			orderBy = append(orderBy, fmt.Sprintf("%s %s", f.Name, sort.Order))
+			applied = append(applied, SortBy{Field: f.PublicName(), Order: sort.Order})
 		}
 	}
 
 	if len(orderBy) > 0 {
 		qb.OrderByClause = " ORDER BY " + strings.Join(orderBy, ", ")
 	}
+	return applied
 }
 
 // AddPagination добавляет пагинацию LIMIT и OFFSET к запросу.
@@ -226,23 +403,88 @@ type ListQuery struct {
 	CountQuery   string
 	SelectParams []interface{}
 	CountParams  []interface{}
+	// Sort примененные сортировки с публичными именами полей, для PagebleRs.Sort
+	Sort []SortBy
+	// Fields запрошенные поля с публичными именами, для PagebleRs.Fields
+	Fields []string
+	// keyset параметры курсорной пагинации, nil для LIMIT/OFFSET
+	keyset *keyset
 }
 
-func BuildListQuery(req PagebleRq, baseSelectQuery string, fieldValidator func(string) bool) *ListQuery {
+// BuildListQuery строит запросы списка и подсчета по параметрам запроса.
+// Ошибки во всех фильтрах, включая req.Where, собираются в один *QueryError. Для req.Strict ошибками
+// также считаются фильтры по полям не из схемы и сортировка по несортируемым полям.
+// В режиме курсора (req.CursorMode) вместо OFFSET строится условие по ключам сортировки,
+// а CountQuery пуст, если не запрошен req.WithTotal.
+func BuildListQuery(req PagebleRq, baseSelectQuery string, schema Schema) (*ListQuery, error) {
 	qb := NewQueryBuilder()
 
-	for field, predicate := range req.Filter {
-		qb.AddFilter(field, predicate, fieldValidator)
+	// Фильтры обходятся в порядке имен, чтобы номера параметров и ошибки не зависели от порядка map
+	fields := make([]string, 0, len(req.Filter))
+	for field := range req.Filter {
+		fields = append(fields, field)
 	}
+	sort.Strings(fields)
 
-	qb.AddSort(req.Sort, fieldValidator)
+	errs := &QueryError{}
+	if req.Strict {
+		for _, field := range fields {
+			if _, ok := schema.Lookup(field); !ok {
+				errs.add(field, "unknown field")
+			}
+		}
+		for _, s := range req.Sort {
+			if f, ok := schema.Lookup(s.Field); !ok || !f.Sortable {
+				errs.add("sort", "sorting by %q is not allowed", s.Field)
+			}
+		}
+	}
+	public, fieldErrs := publicFields(req.Fields, schema)
+	errs.Params = append(errs.Params, fieldErrs.Params...)
+	for _, field := range fields {
+		if err := qb.AddFilter(field, req.Filter[field], schema); err != nil {
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				return nil, err
+			}
+			errs.Params = append(errs.Params, qe.Params...)
+		}
+	}
+	if req.Where != nil {
+		if err := qb.AddWhere(req.Where, schema, req.Strict); err != nil {
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				return nil, err
+			}
+			errs.Params = append(errs.Params, qe.Params...)
+		}
+	}
+	if err := errs.orNil(); err != nil {
+		return nil, err
+	}
 
-	qb.AddPagination(req.Page, req.Size)
+	// COUNT строится до условия курсора: общее количество не зависит от текущей страницы
+	q := &ListQuery{
+		CountQuery:  qb.BuildCountQuery(baseSelectQuery),
+		CountParams: slices.Clone(qb.Params),
+		Fields:      public,
+	}
 
-	return &ListQuery{
-		SelectQuery:  qb.BuildSelectQuery(baseSelectQuery),
-		CountQuery:   qb.BuildCountQuery(baseSelectQuery),
-		SelectParams: qb.GetParams(),
-		CountParams:  qb.GetCountParams(),
+	if req.CursorMode {
+		ks, sorts, err := qb.addKeyset(req, schema)
+		if err != nil {
+			return nil, err
+		}
+		q.keyset, q.Sort = ks, sorts
+		if !req.WithTotal {
+			q.CountQuery, q.CountParams = "", nil
+		}
+	} else {
+		q.Sort = qb.AddSort(req.Sort, schema)
+		qb.AddPagination(req.Page, req.Size)
 	}
+
+	q.SelectQuery = qb.BuildSelectQuery(baseSelectQuery)
+	q.SelectParams = qb.GetParams()
+	return q, nil
 }
@@ -1,29 +1,32 @@
 package entityreqdecorator
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
 
-func testFieldValidator(field string) bool {
-	allowedFields := map[string]bool{
-		"name":    true,
-		"age":     true,
-		"email":   true,
-		"id":      true,
-		"status":  true,
-		"created": true,
-	}
-	return allowedFields[field]
+var testSchema = Schema{
+	{Name: "name", Type: TypeText, Filterable: true, Sortable: true},
+	{Name: "age", Type: TypeInt, Filterable: true, Sortable: true},
+	{Name: "email", Type: TypeText, Filterable: true, Sortable: true, Operators: []string{"eq", "like", "ilike", "endswith"}},
+	{Name: "id", Type: TypeInt, Filterable: true, Sortable: true},
+	{Name: "status", Type: TypeText, Filterable: true, Sortable: true},
+	{Name: "created", Type: TypeTimestamp, Filterable: true, Sortable: true},
+	{Name: "period_start", Alias: "periodStart", Type: TypeDate, Filterable: true, Sortable: true},
+	{Name: "active", Type: TypeBool, Filterable: true},
+	{Name: "password", Type: TypeText},
 }
+
 func TestAddFilter(t *testing.T) {
 	tests := []struct {
 		name          string
 		field         string
 		predicate     SQLGenerator
-		fieldValidator func(string) bool
+		schema        Schema
 		wantCondition string
 		wantParams    []interface{}
+		wantErr       bool
 	}{
 		{
 			name:  "PredicateEQ with valid field",
@@ -33,7 +36,7 @@ func TestAddFilter(t *testing.T) {
 					Value: "John",
 				},
 			},
-			fieldValidator: testFieldValidator,
+			schema:        testSchema,
 			wantCondition: "name = $1",
 			wantParams:    []interface{}{"John"},
 		},
@@ -45,7 +48,7 @@ func TestAddFilter(t *testing.T) {
 					Value: "test",
 				},
 			},
-			fieldValidator: testFieldValidator,
+			schema:        testSchema,
 			wantCondition: "email LIKE $1",
 			wantParams:    []interface{}{"%test%"},
 		},
@@ -64,16 +67,254 @@ func TestAddFilter(t *testing.T) {
 					},
 				},
 			},
-			fieldValidator: testFieldValidator,
+			schema:        testSchema,
 			wantCondition: "(age > $1 AND age < $2)",
-			wantParams:    []interface{}{"18", "65"},
+			wantParams:    []interface{}{int64(18), int64(65)},
+		},
+		{
+			name:  "PredicateORF nested in PredicateANF",
+			field: "age",
+			predicate: &PredicateANF{
+				Predicate: Predicate{
+					InnerPredicate: []SQLGenerator{
+						&PredicateGTE{
+							Predicate: Predicate{Value: "2019"},
+						},
+						&PredicateORF{
+							Predicate: Predicate{
+								InnerPredicate: []SQLGenerator{
+									&PredicateLT{
+										Predicate: Predicate{Value: "2015"},
+									},
+									&PredicateEQ{
+										Predicate: Predicate{Value: "2017"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "(age >= $1 AND (age < $2 OR age = $3))",
+			wantParams:    []interface{}{int64(2019), int64(2015), int64(2017)},
+		},
+		{
+			name:  "PredicateIN",
+			field: "id",
+			predicate: &PredicateIN{
+				Predicate: Predicate{
+					Values: []string{"1", "2", "3"},
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "id IN ($1, $2, $3)",
+			wantParams:    []interface{}{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "PredicateNIN",
+			field: "status",
+			predicate: &PredicateNIN{
+				Predicate: Predicate{
+					Values: []string{"spam"},
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "status NOT IN ($1)",
+			wantParams:    []interface{}{"spam"},
+		},
+		{
+			name:  "PredicateIN with empty list",
+			field: "id",
+			predicate: &PredicateIN{
+				Predicate: Predicate{},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+		},
+		{
+			name:          "PredicateIsNull",
+			field:         "created",
+			predicate:     &PredicateIsNull{},
+			schema:        testSchema,
+			wantCondition: "created IS NULL",
+			wantParams:    []interface{}{},
+		},
+		{
+			name:          "PredicateNotNull",
+			field:         "created",
+			predicate:     &PredicateNotNull{},
+			schema:        testSchema,
+			wantCondition: "created IS NOT NULL",
+			wantParams:    []interface{}{},
+		},
+		{
+			name:  "PredicateBetween",
+			field: "age",
+			predicate: &PredicateBetween{
+				Predicate: Predicate{
+					Values: []string{"18", "65"},
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "age BETWEEN $1 AND $2",
+			wantParams:    []interface{}{int64(18), int64(65)},
+		},
+		{
+			name:  "PredicateBetween with one bound",
+			field: "age",
+			predicate: &PredicateBetween{
+				Predicate: Predicate{
+					Values: []string{"18"},
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+			wantErr:       true,
+		},
+		{
+			name:  "PredicateILike escapes wildcards",
+			field: "name",
+			predicate: &PredicateILike{
+				Predicate: Predicate{
+					Value: `50%_off\`,
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "name ILIKE $1",
+			wantParams:    []interface{}{`%50\%\_off\\%`},
+		},
+		{
+			name:  "PredicateStartsWith",
+			field: "name",
+			predicate: &PredicateStartsWith{
+				Predicate: Predicate{
+					Value: "Go",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "name ILIKE $1",
+			wantParams:    []interface{}{"Go%"},
+		},
+		{
+			name:  "PredicateEndsWith",
+			field: "email",
+			predicate: &PredicateEndsWith{
+				Predicate: Predicate{
+					Value: "@mail_ru",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "email ILIKE $1",
+			wantParams:    []interface{}{`%@mail\_ru`},
+		},
+		{
+			name:  "unknown field is skipped",
+			field: "login",
+			predicate: &PredicateEQ{
+				Predicate: Predicate{
+					Value: "x",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+		},
+		{
+			name:  "field is not filterable",
+			field: "password",
+			predicate: &PredicateEQ{
+				Predicate: Predicate{
+					Value: "x",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+			wantErr:       true,
+		},
+		{
+			name:  "operator is not allowed for field",
+			field: "email",
+			predicate: &PredicateStartsWith{
+				Predicate: Predicate{
+					Value: "admin",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+			wantErr:       true,
+		},
+		{
+			name:  "like is not allowed for integer field",
+			field: "age",
+			predicate: &PredicateLike{
+				Predicate: Predicate{
+					Value: "1",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+			wantErr:       true,
+		},
+		{
+			name:  "invalid integer value",
+			field: "age",
+			predicate: &PredicateGT{
+				Predicate: Predicate{
+					Value: "eighteen",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+			wantErr:       true,
+		},
+		{
+			name:  "invalid value inside group leaves no params",
+			field: "age",
+			predicate: &PredicateANF{
+				Predicate: Predicate{
+					InnerPredicate: []SQLGenerator{
+						&PredicateGT{
+							Predicate: Predicate{Value: "18"},
+						},
+						&PredicateLT{
+							Predicate: Predicate{Value: "old"},
+						},
+					},
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "",
+			wantParams:    []interface{}{},
+			wantErr:       true,
+		},
+		{
+			name:  "bool value",
+			field: "active",
+			predicate: &PredicateEQ{
+				Predicate: Predicate{
+					Value: "true",
+				},
+			},
+			schema:        testSchema,
+			wantCondition: "active = $1",
+			wantParams:    []interface{}{true},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			qb := NewQueryBuilder()
-			qb.AddFilter(tt.field, tt.predicate, tt.fieldValidator)
+			err := qb.AddFilter(tt.field, tt.predicate, tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
 
 			// Проверяем условие WHERE
 			whereClause := qb.BuildWhereClause()
@@ -102,18 +343,18 @@ func TestAddFilter(t *testing.T) {
 
 func TestAddSort(t *testing.T) {
 	tests := []struct {
-		name          string
-		sorts         []SortBy
-		fieldValidator func(string) bool
-		wantOrderBy   string
+		name        string
+		sorts       []SortBy
+		schema      Schema
+		wantOrderBy string
 	}{
 		{
 			name: "single valid sort",
 			sorts: []SortBy{
 				{Field: "name", Order: "ASC"},
 			},
-			fieldValidator: testFieldValidator,
-			wantOrderBy:   " ORDER BY name ASC",
+			schema:      testSchema,
+			wantOrderBy: " ORDER BY name ASC",
 		},
 		{
 			name: "multiple valid sorts",
@@ -121,8 +362,8 @@ func TestAddSort(t *testing.T) {
 				{Field: "name", Order: "ASC"},
 				{Field: "id", Order: "DESC"},
 			},
-			fieldValidator: testFieldValidator,
-			wantOrderBy:   " ORDER BY name ASC, id DESC",
+			schema:      testSchema,
+			wantOrderBy: " ORDER BY name ASC, id DESC",
 		},
 		{
 			name: "mixed valid and invalid sorts",
@@ -131,15 +372,15 @@ func TestAddSort(t *testing.T) {
 				{Field: "invalid_field", Order: "DESC"},
 				{Field: "email", Order: "ASC"},
 			},
-			fieldValidator: testFieldValidator,
-			wantOrderBy:   " ORDER BY name ASC, email ASC",
+			schema:      testSchema,
+			wantOrderBy: " ORDER BY name ASC, email ASC",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			qb := NewQueryBuilder()
-			qb.AddSort(tt.sorts, tt.fieldValidator)
+			qb.AddSort(tt.sorts, tt.schema)
 
 			if qb.OrderByClause != tt.wantOrderBy {
 				t.Errorf("OrderByClause = %v, want %v", qb.OrderByClause, tt.wantOrderBy)
@@ -150,12 +391,12 @@ func TestAddSort(t *testing.T) {
 
 func TestAddPagination(t *testing.T) {
 	tests := []struct {
-		name        string
-		page        int
-		size        int
-		wantLimit   string
-		wantOffset  string
-		wantParams  []interface{}
+		name       string
+		page       int
+		size       int
+		wantLimit  string
+		wantOffset string
+		wantParams []interface{}
 	}{
 		{
 			name:       "first page with limit",
@@ -194,7 +435,7 @@ func TestAddPagination(t *testing.T) {
 			if qb.OffsetClause != tt.wantOffset {
 				t.Errorf("OffsetClause = %v, want %v", qb.OffsetClause, tt.wantOffset)
 			}
-			
+
 			params := qb.GetParams()
 			if len(params) != len(tt.wantParams) {
 				t.Errorf("GetParams() length = %v, want %v", len(params), len(tt.wantParams))
@@ -208,11 +449,10 @@ func TestAddPagination(t *testing.T) {
 	}
 }
 
-
 func TestBuildWhereClause(t *testing.T) {
 	tests := []struct {
-		name           string
-		setupBuilder   func(*QueryBuilder)
+		name            string
+		setupBuilder    func(*QueryBuilder)
 		wantWhereClause string
 	}{
 		{
@@ -227,7 +467,7 @@ func TestBuildWhereClause(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("name", &PredicateEQ{
 					Predicate: Predicate{Value: "John"},
-				}, testFieldValidator)
+				}, testSchema)
 			},
 			wantWhereClause: " WHERE name = $1",
 		},
@@ -236,10 +476,10 @@ func TestBuildWhereClause(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("name", &PredicateEQ{
 					Predicate: Predicate{Value: "John"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddFilter("age", &PredicateGT{
 					Predicate: Predicate{Value: "18"},
-				}, testFieldValidator)
+				}, testSchema)
 			},
 			wantWhereClause: " WHERE name = $1 AND age > $2",
 		},
@@ -249,7 +489,7 @@ func TestBuildWhereClause(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			qb := NewQueryBuilder()
 			tt.setupBuilder(qb)
-			
+
 			whereClause := qb.BuildWhereClause()
 			if whereClause != tt.wantWhereClause {
 				t.Errorf("BuildWhereClause() = %v, want %v", whereClause, tt.wantWhereClause)
@@ -258,7 +498,6 @@ func TestBuildWhereClause(t *testing.T) {
 	}
 }
 
-
 func TestBuildSelectQuery(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -280,10 +519,10 @@ func TestBuildSelectQuery(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("name", &PredicateEQ{
 					Predicate: Predicate{Value: "John"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddSort([]SortBy{
 					{Field: "id", Order: "DESC"},
-				}, testFieldValidator)
+				}, testSchema)
 			},
 			wantQuery: "SELECT id, name FROM users WHERE name = $1 ORDER BY id DESC",
 		},
@@ -293,11 +532,11 @@ func TestBuildSelectQuery(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("status", &PredicateEQ{
 					Predicate: Predicate{Value: "active"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddSort([]SortBy{
 					{Field: "created", Order: "DESC"},
 					{Field: "name", Order: "ASC"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddPagination(2, 10)
 			},
 			wantQuery: "SELECT * FROM products WHERE status = $1 ORDER BY created DESC, name ASC LIMIT $2 OFFSET $3",
@@ -308,7 +547,7 @@ func TestBuildSelectQuery(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			qb := NewQueryBuilder()
 			tt.setupBuilder(qb)
-			
+
 			query := qb.BuildSelectQuery(tt.baseQuery)
 			if query != tt.wantQuery {
 				t.Errorf("BuildSelectQuery() = %v, want %v", query, tt.wantQuery)
@@ -338,10 +577,10 @@ func TestBuildCountQuery(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("name", &PredicateLike{
 					Predicate: Predicate{Value: "John"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddFilter("age", &PredicateGT{
 					Predicate: Predicate{Value: "18"},
-				}, testFieldValidator)
+				}, testSchema)
 			},
 			wantQuery: "SELECT COUNT(*) FROM (SELECT id, name FROM users WHERE name LIKE $1 AND age > $2) as subquery",
 		},
@@ -351,7 +590,7 @@ func TestBuildCountQuery(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("status", &PredicateEQ{
 					Predicate: Predicate{Value: "active"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddPagination(2, 10) // Добавляем пагинацию, но она не должна влиять на COUNT
 			},
 			wantQuery: "SELECT COUNT(*) FROM (SELECT * FROM products WHERE status = $1) as subquery",
@@ -362,12 +601,12 @@ func TestBuildCountQuery(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			qb := NewQueryBuilder()
 			tt.setupBuilder(qb)
-			
+
 			query := qb.BuildCountQuery(tt.baseQuery)
 			if query != tt.wantQuery {
 				t.Errorf("BuildCountQuery() = %v, want %v", query, tt.wantQuery)
 			}
-			
+
 			// Проверяем, что COUNT запрос не содержит LIMIT и OFFSET
 			if strings.Contains(strings.ToUpper(query), "LIMIT") {
 				t.Errorf("BuildCountQuery() contains LIMIT, but shouldn't")
@@ -390,19 +629,19 @@ func TestGetCountParams(t *testing.T) {
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("name", &PredicateEQ{
 					Predicate: Predicate{Value: "John"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddFilter("age", &PredicateGT{
 					Predicate: Predicate{Value: "18"},
-				}, testFieldValidator)
+				}, testSchema)
 			},
-			wantParams: []interface{}{"John", "18"},
+			wantParams: []interface{}{"John", int64(18)},
 		},
 		{
 			name: "where conditions with pagination",
 			setupBuilder: func(qb *QueryBuilder) {
 				qb.AddFilter("status", &PredicateEQ{
 					Predicate: Predicate{Value: "active"},
-				}, testFieldValidator)
+				}, testSchema)
 				qb.AddPagination(2, 10)
 			},
 			wantParams: []interface{}{"active"},
@@ -420,13 +659,13 @@ func TestGetCountParams(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			qb := NewQueryBuilder()
 			tt.setupBuilder(qb)
-			
+
 			params := qb.GetCountParams()
-			
+
 			if len(params) != len(tt.wantParams) {
 				t.Errorf("GetCountParams() length = %v, want %v", len(params), len(tt.wantParams))
 			}
-			
+
 			for i, param := range params {
 				if param != tt.wantParams[i] {
 					t.Errorf("GetCountParams()[%d] = %v, want %v", i, param, tt.wantParams[i])
@@ -435,3 +674,216 @@ func TestGetCountParams(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildListQuery(t *testing.T) {
+	req := PagebleRq{
+		Page: 1,
+		Size: 10,
+		Sort: []SortBy{{Field: "age", Order: "DESC"}},
+		Filter: map[string]SQLGenerator{
+			"name": &PredicateILike{Predicate: Predicate{Value: "jo"}},
+			"age":  &PredicateBetween{Predicate: Predicate{Values: []string{"18", "65"}}},
+		},
+	}
+
+	q, err := BuildListQuery(req, "SELECT * FROM users", testSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery := "SELECT * FROM users WHERE age BETWEEN $1 AND $2 AND name ILIKE $3 ORDER BY age DESC LIMIT $4"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	wantParams := []interface{}{int64(18), int64(65), "%jo%"}
+	if len(q.CountParams) != len(wantParams) {
+		t.Fatalf("CountParams = %v, want %v", q.CountParams, wantParams)
+	}
+	for i, param := range q.CountParams {
+		if param != wantParams[i] {
+			t.Errorf("CountParams[%d] = %v, want %v", i, param, wantParams[i])
+		}
+	}
+}
+
+func TestBuildListQueryErrors(t *testing.T) {
+	req := PagebleRq{
+		Filter: map[string]SQLGenerator{
+			"id":       &PredicateEQ{Predicate: Predicate{Value: "abc"}},
+			"created":  &PredicateGT{Predicate: Predicate{Value: "yesterday"}},
+			"password": &PredicateEQ{Predicate: Predicate{Value: "x"}},
+			"name":     &PredicateEQ{Predicate: Predicate{Value: "John"}},
+		},
+	}
+
+	q, err := BuildListQuery(req, "SELECT * FROM users", testSchema)
+	if q != nil {
+		t.Errorf("BuildListQuery() = %v, want nil", q)
+	}
+	var qe *QueryError
+	if !errors.As(err, &qe) {
+		t.Fatalf("BuildListQuery() error = %v, want *QueryError", err)
+	}
+	var params []string
+	for _, p := range qe.Params {
+		params = append(params, p.Param)
+	}
+	wantParams := []string{"created", "id", "password"}
+	if strings.Join(params, ",") != strings.Join(wantParams, ",") {
+		t.Errorf("QueryError params = %v, want %v", params, wantParams)
+	}
+}
+
+func TestBuildListQueryStrict(t *testing.T) {
+	req := PagebleRq{
+		Strict: true,
+		Sort: []SortBy{
+			{Field: "titel", Order: "ASC"},
+			{Field: "active", Order: "DESC"},
+			{Field: "name", Order: "ASC"},
+		},
+		Filter: map[string]SQLGenerator{
+			"nmae": &PredicateEQ{Predicate: Predicate{Value: "John"}},
+		},
+	}
+
+	_, err := BuildListQuery(req, "SELECT * FROM users", testSchema)
+	var qe *QueryError
+	if !errors.As(err, &qe) {
+		t.Fatalf("BuildListQuery() error = %v, want *QueryError", err)
+	}
+	want := []ParamError{
+		{Param: "nmae", Message: "unknown field"},
+		{Param: "sort", Message: `sorting by "titel" is not allowed`},
+		{Param: "sort", Message: `sorting by "active" is not allowed`},
+	}
+	if len(qe.Params) != len(want) {
+		t.Fatalf("QueryError params = %v, want %v", qe.Params, want)
+	}
+	for i := range want {
+		if qe.Params[i] != want[i] {
+			t.Errorf("QueryError params[%d] = %v, want %v", i, qe.Params[i], want[i])
+		}
+	}
+
+	// В нестрогом режиме неизвестные поля пропускаются
+	req.Strict = false
+	q, err := BuildListQuery(req, "SELECT * FROM users", testSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	if want := "SELECT * FROM users ORDER BY name ASC"; q.SelectQuery != want {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, want)
+	}
+}
+
+func TestBuildListQueryAliases(t *testing.T) {
+	req := PagebleRq{
+		Strict: true,
+		Sort: []SortBy{
+			{Field: "periodStart", Order: "DESC"},
+			{Field: "id", Order: "ASC"},
+		},
+		Filter: map[string]SQLGenerator{
+			"periodStart": &PredicateGTE{Predicate: Predicate{Value: "2019-01-01"}},
+		},
+	}
+
+	q, err := BuildListQuery(req, "SELECT * FROM work_history", testSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery := "SELECT * FROM work_history WHERE period_start >= $1 ORDER BY period_start DESC, id ASC"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+
+	// Сортировка по имени колонки тоже принимается, но в ответе возвращается публичное имя
+	req.Sort = []SortBy{{Field: "period_start", Order: "ASC"}}
+	q, err = BuildListQuery(req, "SELECT * FROM work_history", testSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantSort := []SortBy{{Field: "periodStart", Order: "ASC"}}
+	if len(q.Sort) != 1 || q.Sort[0] != wantSort[0] {
+		t.Errorf("Sort = %v, want %v", q.Sort, wantSort)
+	}
+}
+
+var relationSchema = Schema{
+	{Name: "id", Type: TypeInt, Filterable: true, Sortable: true},
+	{Name: "title", Type: TypeText, Filterable: true, Sortable: true},
+	{Name: "tag", Type: TypeText, Filterable: true, IgnoreCase: true, Operators: []string{"eq", "in", "ilike"},
+		Relation: &RelationFilter{
+			Relation: Relation{
+				Table:        "technologies_tag",
+				OwnerTable:   "technology",
+				OwnerColumn:  "technology_id",
+				TargetTable:  "tag",
+				TargetColumn: "tag_id",
+			},
+			Column: "name",
+		}},
+}
+
+func TestBuildListQueryRelationFilter(t *testing.T) {
+	req := ParseQueryParams(map[string][]string{
+		"tag":   {"in(Backend,cloud)"},
+		"title": {"startswith(go)"},
+	})
+	q, err := BuildListQuery(req, "SELECT id, title FROM technology", relationSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery := "SELECT id, title FROM technology WHERE EXISTS (SELECT 1 FROM technologies_tag l JOIN tag t ON t.id = l.tag_id " +
+		"WHERE l.technology_id = technology.id AND lower(t.name) IN (lower($1), lower($2))) AND title ILIKE $3 LIMIT $4"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	wantParams := []interface{}{"Backend", "cloud", "go%"}
+	for i, param := range q.CountParams {
+		if param != wantParams[i] {
+			t.Errorf("CountParams[%d] = %v, want %v", i, param, wantParams[i])
+		}
+	}
+
+	// Поле-связь из тела POST /search объединяется с условиями по колонкам
+	where := &PredicateORF{Predicate: Predicate{InnerPredicate: []SQLGenerator{
+		&PredicateEQ{Predicate: Predicate{Field: "tag", Value: "backend"}},
+		&PredicateEQ{Predicate: Predicate{Field: "id", Value: "7"}},
+	}}}
+	q, err = BuildListQuery(PagebleRq{Where: where, Strict: true}, "SELECT id, title FROM technology", relationSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery = "SELECT id, title FROM technology WHERE (EXISTS (SELECT 1 FROM technologies_tag l JOIN tag t ON t.id = l.tag_id " +
+		"WHERE l.technology_id = technology.id AND lower(t.name) = lower($1)) OR id = $2)"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+}
+
+func TestRelationFieldIsNotAColumn(t *testing.T) {
+	columns, err := SelectColumns(PagebleRq{}, relationSchema)
+	if err != nil {
+		t.Fatalf("SelectColumns() error = %v", err)
+	}
+	if got := ColumnList(columns); got != "id, title" {
+		t.Errorf("ColumnList() = %q, want %q", got, "id, title")
+	}
+
+	req := PagebleRq{Fields: []string{"tag"}, Strict: true, Sort: []SortBy{{Field: "tag", Order: "ASC"}}}
+	if _, err := SelectColumns(req, relationSchema); err == nil {
+		t.Errorf("SelectColumns() with fields=tag: want error")
+	}
+	_, err = BuildListQuery(req, "SELECT id FROM technology", relationSchema)
+	var qe *QueryError
+	if !errors.As(err, &qe) || len(qe.Params) != 2 {
+		t.Errorf("BuildListQuery() error = %v, want sort and fields errors", err)
+	}
+
+	// ne не разрешен для поля-связи
+	req = PagebleRq{Filter: map[string]SQLGenerator{"tag": &PredicateNE{Predicate: Predicate{Value: "x"}}}}
+	if _, err := BuildListQuery(req, "SELECT id FROM technology", relationSchema); !errors.As(err, &qe) {
+		t.Errorf("BuildListQuery() with tag=ne(x): want *QueryError, got %v", err)
+	}
+}
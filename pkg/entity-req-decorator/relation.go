@@ -0,0 +1,189 @@
+package entityreqdecorator
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+// MissingError ссылки на несуществующие записи при изменении связей.
+// Обработчики отдают ее клиенту с кодом 422.
+type MissingError struct {
+	Entity string
+	IDs    []int64
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("%s with ids %v not found", e.Entity, e.IDs)
+}
+
+// foreignKeyViolation код ошибки Postgres при нарушении внешнего ключа
+const foreignKeyViolation = "23503"
+
+// Relation связь многие-ко-многим через таблицу связей.
+// Owner — запись, от которой идет связь, Target — связанные записи:
+// для тегов технологии Owner — technology, Target — tag.
+type Relation struct {
+	// Table таблица связей: technologies_tag
+	Table string
+	// OwnerTable, OwnerColumn, OwnerName таблица, колонка в таблице связей и имя сущности-владельца
+	OwnerTable, OwnerColumn, OwnerName string
+	// TargetTable, TargetColumn, TargetName то же для связанных записей
+	TargetTable, TargetColumn, TargetName string
+}
+
+// Reverse возвращает ту же связь с другой стороны: теги технологии -> технологии тега
+func (rel Relation) Reverse() Relation {
+	return Relation{
+		Table:        rel.Table,
+		OwnerTable:   rel.TargetTable,
+		OwnerColumn:  rel.TargetColumn,
+		OwnerName:    rel.TargetName,
+		TargetTable:  rel.OwnerTable,
+		TargetColumn: rel.OwnerColumn,
+		TargetName:   rel.OwnerName,
+	}
+}
+
+// Set заменяет набор связанных записей ownerID на ids в отдельной транзакции, см. SetTx
+func (rel Relation) Set(db *sql.DB, ownerID int64, ids []int64) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = rel.SetTx(tx, ownerID, ids); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetTx заменяет набор связанных записей ownerID на ids в транзакции tx. Строка владельца
+// блокируется, текущие связи сравниваются с ids, лишние удаляются, недостающие добавляются.
+// Возвращает ErrNotFound, если владельца нет, и *MissingError со списком ids,
+// которых нет в TargetTable; транзакцию после ошибки нужно откатить.
+func (rel Relation) SetTx(tx *sql.Tx, ownerID int64, ids []int64) error {
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+
+	// Блокировка владельца не дает параллельным запросам менять связи одновременно
+	var id int64
+	err := tx.QueryRow("SELECT id FROM "+rel.OwnerTable+" WHERE id = $1 FOR UPDATE", ownerID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s with id %d %w", rel.OwnerName, ownerID, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", rel.OwnerName, err)
+	}
+
+	existing, err := queryIDs(tx, "SELECT id FROM "+rel.TargetTable+" WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to check %s ids: %w", rel.TargetName, err)
+	}
+	if missing := difference(ids, existing); len(missing) > 0 {
+		return &MissingError{Entity: rel.TargetName, IDs: missing}
+	}
+
+	current, err := queryIDs(tx, "SELECT "+rel.TargetColumn+" FROM "+rel.Table+" WHERE "+rel.OwnerColumn+" = $1", ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s list: %w", rel.OwnerName, rel.TargetName, err)
+	}
+
+	if removed := difference(current, ids); len(removed) > 0 {
+		query := "DELETE FROM " + rel.Table + " WHERE " + rel.OwnerColumn + " = $1 AND " + rel.TargetColumn + " = ANY($2)"
+		if _, err := tx.Exec(query, ownerID, pq.Array(removed)); err != nil {
+			return fmt.Errorf("failed to remove %s %s: %w", rel.OwnerName, rel.TargetName, err)
+		}
+	}
+	if added := difference(ids, current); len(added) > 0 {
+		query := "INSERT INTO " + rel.Table + " (" + rel.OwnerColumn + ", " + rel.TargetColumn + ") " +
+			"SELECT $1, unnest($2::bigint[]) ON CONFLICT DO NOTHING"
+		if _, err := tx.Exec(query, ownerID, pq.Array(added)); err != nil {
+			// Связанную запись удалили после проверки
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+				return &MissingError{Entity: rel.TargetName, IDs: added}
+			}
+			return fmt.Errorf("failed to add %s %s: %w", rel.OwnerName, rel.TargetName, err)
+		}
+	}
+	return nil
+}
+
+// Related возвращает записи таблицы репозитория, связанные с ownerID через rel,
+// в порядке id. rel.TargetTable должна совпадать с таблицей репозитория.
+// Возвращает ErrNotFound, если владельца нет.
+func (r *Repository[T]) Related(rel Relation, ownerID int64) ([]T, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM "+rel.OwnerTable+" WHERE id = $1)", ownerID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", rel.OwnerName, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s with id %d %w", rel.OwnerName, ownerID, ErrNotFound)
+	}
+
+	columns := r.schema.Columns()
+	query := "SELECT " + ColumnList(columns) + " FROM " + r.table +
+		" WHERE id IN (SELECT " + rel.TargetColumn + " FROM " + rel.Table + " WHERE " + rel.OwnerColumn + " = $1)" +
+		" ORDER BY id"
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s %s list: %w", rel.OwnerName, r.name, err)
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := rows.Scan(ScanTargets(columns, r.columns(&item))...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", r.name, err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return items, nil
+}
+
+// queryIDs выполняет запрос, возвращающий одну колонку id
+func queryIDs(tx *sql.Tx, query string, args ...interface{}) ([]int64, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// difference возвращает элементы a, которых нет в b, в порядке a
+func difference(a, b []int64) []int64 {
+	var diff []int64
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			diff = append(diff, id)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,60 @@
+package entityreqdecorator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRelationReverse(t *testing.T) {
+	rel := Relation{
+		Table:        "technologies_tag",
+		OwnerTable:   "technology",
+		OwnerColumn:  "technology_id",
+		OwnerName:    "technology",
+		TargetTable:  "tag",
+		TargetColumn: "tag_id",
+		TargetName:   "tag",
+	}
+	got := rel.Reverse()
+	want := Relation{
+		Table:        "technologies_tag",
+		OwnerTable:   "tag",
+		OwnerColumn:  "tag_id",
+		OwnerName:    "tag",
+		TargetTable:  "technology",
+		TargetColumn: "technology_id",
+		TargetName:   "technology",
+	}
+	if got != want {
+		t.Errorf("Reverse() = %+v, want %+v", got, want)
+	}
+	if got.Reverse() != rel {
+		t.Errorf("Reverse().Reverse() = %+v, want %+v", got.Reverse(), rel)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int64
+		want []int64
+	}{
+		{name: "лишние и недостающие", a: []int64{1, 2, 3}, b: []int64{2, 4}, want: []int64{1, 3}},
+		{name: "совпадают", a: []int64{1, 2}, b: []int64{2, 1}, want: nil},
+		{name: "пустое b", a: []int64{5}, b: nil, want: []int64{5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := difference(tt.a, tt.b); !slices.Equal(got, tt.want) {
+				t.Errorf("difference(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingErrorMessage(t *testing.T) {
+	err := &MissingError{Entity: "tag", IDs: []int64{9, 11}}
+	if got, want := err.Error(), "tag with ids [9 11] not found"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package entityreqdecorator
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrNotFound запись не найдена. Репозитории оборачивают его в сообщение
+// "tag with id 5 not found", обработчики по нему отдают 404.
+var ErrNotFound = errors.New("not found")
+
+// Repository общая часть репозитория сущности: список с фильтрацией, сортировкой и
+// пагинацией, получение по id и удаление. Встраивается в репозитории сущностей,
+// которые добавляют к нему Create, Update и свои запросы.
+type Repository[T any] struct {
+	db     *sql.DB
+	table  string
+	name   string
+	schema Schema
+	// columns сопоставляет колонки с полями модели для сканирования выбранных колонок
+	columns func(*T) map[string]interface{}
+}
+
+// NewRepository создает репозиторий для таблицы table.
+// name: имя сущности в сообщениях об ошибках ("work history")
+// schema: колонки таблицы; в Get выбираются все колонки схемы, в List — по req.Fields
+// columns: сопоставляет имя колонки с полем модели, например {"id": &t.ID, "name": &t.Name}
+func NewRepository[T any](db *sql.DB, table, name string, schema Schema, columns func(*T) map[string]interface{}) *Repository[T] {
+	return &Repository[T]{
+		db:      db,
+		table:   table,
+		name:    name,
+		schema:  schema,
+		columns: columns,
+	}
+}
+
+// Schema возвращает схему полей таблицы
+func (r *Repository[T]) Schema() Schema {
+	return r.schema
+}
+
+// Get получает одну запись по ID
+func (r *Repository[T]) Get(id int64) (T, error) {
+	columns := r.schema.Columns()
+	query := "SELECT " + ColumnList(columns) + " FROM " + r.table + " WHERE id = $1"
+
+	var item T
+	err := r.db.QueryRow(query, id).Scan(ScanTargets(columns, r.columns(&item))...)
+	if err == sql.ErrNoRows {
+		return item, fmt.Errorf("%s with id %d %w", r.name, id, ErrNotFound)
+	}
+	if err != nil {
+		return item, fmt.Errorf("failed to get %s: %w", r.name, err)
+	}
+	return item, nil
+}
+
+// List получает список записей с пагинацией, сортировкой и фильтрацией.
+// Ошибки в параметрах запроса возвращаются обернутым *QueryError.
+func (r *Repository[T]) List(req PagebleRq) (PagebleRs[T], error) {
+	columns, err := SelectColumns(req, r.schema)
+	if err != nil {
+		return PagebleRs[T]{}, fmt.Errorf("failed to build %s list query: %w", r.name, err)
+	}
+	baseQuery := "SELECT " + ColumnList(columns) + " FROM " + r.table
+
+	queryParams, err := BuildListQuery(req, baseQuery, r.schema)
+	if err != nil {
+		return PagebleRs[T]{}, fmt.Errorf("failed to build %s list query: %w", r.name, err)
+	}
+
+	// В режиме курсора общее количество считается только по запросу withTotal
+	var total int
+	if queryParams.CountQuery != "" {
+		err = r.db.QueryRow(queryParams.CountQuery, queryParams.CountParams...).Scan(&total)
+		if err != nil {
+			return PagebleRs[T]{}, fmt.Errorf("failed to count %s list: %w", r.name, err)
+		}
+	}
+
+	rows, err := r.db.Query(queryParams.SelectQuery, queryParams.SelectParams...)
+	if err != nil {
+		return PagebleRs[T]{}, fmt.Errorf("failed to query %s list: %w", r.name, err)
+	}
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		var item T
+		if err := rows.Scan(ScanTargets(columns, r.columns(&item))...); err != nil {
+			return PagebleRs[T]{}, fmt.Errorf("failed to scan %s: %w", r.name, err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return PagebleRs[T]{}, fmt.Errorf("rows error: %w", err)
+	}
+	return NewPage(req, queryParams, items, total)
+}
+
+// Delete удаляет одну запись по ID
+func (r *Repository[T]) Delete(id int64) (int64, error) {
+	query := "DELETE FROM " + r.table + " WHERE id = $1"
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %s: %w", r.name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return 0, fmt.Errorf("%s with id %d %w", r.name, id, ErrNotFound)
+	}
+
+	return id, nil
+}
+
+// DeleteList удаляет записи по списку ID и возвращает ID удаленных записей
+func (r *Repository[T]) DeleteList(ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := "DELETE FROM " + r.table + " WHERE id = ANY($1) RETURNING id"
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete %s list: %w", r.name, err)
+	}
+	defer rows.Close()
+
+	var deletedIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted %s ID: %w", r.name, err)
+		}
+		deletedIDs = append(deletedIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return deletedIDs, nil
+}
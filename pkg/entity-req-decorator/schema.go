@@ -0,0 +1,219 @@
+package entityreqdecorator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType тип колонки в Postgres, к которому приводятся значения фильтров
+type FieldType string
+
+const (
+	TypeText      FieldType = "text"
+	TypeInt       FieldType = "bigint"
+	TypeDate      FieldType = "date"
+	TypeTimestamp FieldType = "timestamptz"
+	TypeBool      FieldType = "boolean"
+	// TypeJSON и TypeTextArray колонки, доступные только для выбора через fields
+	TypeJSON      FieldType = "jsonb"
+	TypeTextArray FieldType = "text[]"
+)
+
+// defaultOperators операторы фильтра, доступные полю по умолчанию в зависимости от типа
+var defaultOperators = map[FieldType][]string{
+	TypeText:      {"eq", "ne", "like", "ilike", "startswith", "endswith", "in", "nin", "isnull", "notnull", "anf", "orf"},
+	TypeInt:       {"eq", "ne", "gt", "gte", "lt", "lte", "in", "nin", "between", "isnull", "notnull", "anf", "orf"},
+	TypeDate:      {"eq", "ne", "gt", "gte", "lt", "lte", "in", "nin", "between", "isnull", "notnull", "anf", "orf"},
+	TypeTimestamp: {"eq", "ne", "gt", "gte", "lt", "lte", "between", "isnull", "notnull", "anf", "orf"},
+	TypeBool:      {"eq", "ne", "isnull", "notnull"},
+}
+
+// Field описание колонки, доступной в запросах списка
+type Field struct {
+	// Name имя колонки в таблице
+	Name string
+	// Alias имя поля в JSON-ответе (periodStart для period_start). В параметрах запроса
+	// принимаются оба имени, в ответе возвращается Alias.
+	Alias      string
+	Type       FieldType
+	Filterable bool
+	Sortable   bool
+	// Nullable колонка допускает NULL; по таким колонкам нельзя сортировать в режиме курсора
+	Nullable bool
+	// Operators допустимые операторы фильтра. Если не заданы — операторы по умолчанию для типа.
+	Operators []string
+	// IgnoreCase операторы сравнения и in/nin для текстового поля не учитывают регистр
+	IgnoreCase bool
+	// Relation фильтр по колонке связанной таблицы. Такое поле доступно только
+	// для фильтрации: его нет в SELECT, fields и сортировке.
+	Relation *RelationFilter
+}
+
+// RelationFilter поле-фильтр по колонке Column связанной таблицы. Условие на колонку
+// строится как для обычного поля и оборачивается в EXISTS по таблице связей:
+// tag=backend для технологий дает
+// EXISTS (SELECT 1 FROM technologies_tag l JOIN tag t ON t.id = l.tag_id
+// WHERE l.technology_id = technology.id AND t.name = $1).
+// OwnerTable связи должна совпадать с таблицей из FROM запроса списка.
+type RelationFilter struct {
+	Relation
+	Column string
+}
+
+// Schema набор колонок сущности. Все колонки можно выбрать через fields,
+// фильтрация и сортировка разрешены по флагам Filterable и Sortable.
+type Schema []Field
+
+// Lookup ищет поле по имени колонки или псевдониму
+func (s Schema) Lookup(name string) (Field, bool) {
+	for _, f := range s {
+		if f.Name == name || (f.Alias != "" && f.Alias == name) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Columns возвращает колонки таблицы без полей-связей
+func (s Schema) Columns() Schema {
+	columns := make(Schema, 0, len(s))
+	for _, f := range s {
+		if f.Relation == nil {
+			columns = append(columns, f)
+		}
+	}
+	return columns
+}
+
+// PublicName возвращает имя поля, под которым оно видно клиентам
+func (f Field) PublicName() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Allows проверяет, что оператор фильтра разрешен для поля
+func (f Field) Allows(operator string) bool {
+	operators := f.Operators
+	if len(operators) == 0 {
+		operators = defaultOperators[f.Type]
+	}
+	return slices.Contains(operators, operator)
+}
+
+// ignoresCase проверяет, что сравнения по полю не учитывают регистр
+func (f Field) ignoresCase() bool {
+	return f.IgnoreCase && f.Type == TypeText
+}
+
+// Parse приводит строковое значение фильтра к типу поля.
+// Даты принимаются в формате 2006-01-02, время — в RFC 3339 или как дата.
+func (f Field) Parse(value string) (interface{}, error) {
+	switch f.Type {
+	case TypeInt:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return v, nil
+	case TypeDate:
+		v, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date, expected YYYY-MM-DD", value)
+		}
+		return v, nil
+	case TypeTimestamp:
+		if v, err := time.Parse(time.RFC3339, value); err == nil {
+			return v, nil
+		}
+		v, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a timestamp, expected RFC 3339 or YYYY-MM-DD", value)
+		}
+		return v, nil
+	case TypeBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// operatorName возвращает имя оператора фильтра, которым задается предикат
+func operatorName(predicate SQLGenerator) string {
+	switch predicate.(type) {
+	case *PredicateEQ:
+		return "eq"
+	case *PredicateNE:
+		return "ne"
+	case *PredicateGT:
+		return "gt"
+	case *PredicateGTE:
+		return "gte"
+	case *PredicateLT:
+		return "lt"
+	case *PredicateLTE:
+		return "lte"
+	case *PredicateLike:
+		return "like"
+	case *PredicateILike:
+		return "ilike"
+	case *PredicateStartsWith:
+		return "startswith"
+	case *PredicateEndsWith:
+		return "endswith"
+	case *PredicateIN:
+		return "in"
+	case *PredicateNIN:
+		return "nin"
+	case *PredicateBetween:
+		return "between"
+	case *PredicateIsNull:
+		return "isnull"
+	case *PredicateNotNull:
+		return "notnull"
+	case *PredicateANF:
+		return "anf"
+	case *PredicateORF:
+		return "orf"
+	}
+	return ""
+}
+
+// ParamError ошибка в параметре запроса списка
+type ParamError struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+// QueryError ошибки в параметрах запроса списка. Обработчики отдают ее клиенту с кодом 400.
+type QueryError struct {
+	Params []ParamError `json:"params"`
+}
+
+func (e *QueryError) Error() string {
+	msgs := make([]string, len(e.Params))
+	for i, p := range e.Params {
+		msgs[i] = p.Param + ": " + p.Message
+	}
+	return "invalid query parameters: " + strings.Join(msgs, "; ")
+}
+
+// add добавляет ошибку параметра
+func (e *QueryError) add(param, format string, args ...interface{}) {
+	e.Params = append(e.Params, ParamError{Param: param, Message: fmt.Sprintf(format, args...)})
+}
+
+// orNil возвращает nil, если ошибок нет
+func (e *QueryError) orNil() error {
+	if len(e.Params) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,116 @@
+package entityreqdecorator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFieldParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   Field
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:  "text",
+			field: Field{Name: "name", Type: TypeText},
+			value: "42",
+			want:  "42",
+		},
+		{
+			name:  "integer",
+			field: Field{Name: "year", Type: TypeInt},
+			value: "2019",
+			want:  int64(2019),
+		},
+		{
+			name:    "invalid integer",
+			field:   Field{Name: "year", Type: TypeInt},
+			value:   "2019.5",
+			wantErr: true,
+		},
+		{
+			name:  "date",
+			field: Field{Name: "period_start", Type: TypeDate},
+			value: "2019-03-01",
+			want:  time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid date",
+			field:   Field{Name: "period_start", Type: TypeDate},
+			value:   "01-03-2019",
+			wantErr: true,
+		},
+		{
+			name:  "timestamp",
+			field: Field{Name: "created_at", Type: TypeTimestamp},
+			value: "2025-01-02T10:00:00Z",
+			want:  time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "timestamp as date",
+			field: Field{Name: "created_at", Type: TypeTimestamp},
+			value: "2025-01-02",
+			want:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "bool",
+			field: Field{Name: "active", Type: TypeBool},
+			value: "false",
+			want:  false,
+		},
+		{
+			name:    "invalid bool",
+			field:   Field{Name: "active", Type: TypeBool},
+			value:   "yes",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.field.Parse(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotTime, ok := got.(time.Time); ok {
+				if !gotTime.Equal(tt.want.(time.Time)) {
+					t.Errorf("Parse() = %v, want %v", got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    Field
+		operator string
+		want     bool
+	}{
+		{"default text operator", Field{Type: TypeText}, "ilike", true},
+		{"range operator on text", Field{Type: TypeText}, "gt", false},
+		{"range operator on date", Field{Type: TypeDate}, "between", true},
+		{"pattern operator on integer", Field{Type: TypeInt}, "like", false},
+		{"explicit operators", Field{Type: TypeText, Operators: []string{"eq"}}, "like", false},
+		{"explicit operator allowed", Field{Type: TypeText, Operators: []string{"eq"}}, "eq", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Allows(tt.operator); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.operator, got, tt.want)
+			}
+		})
+	}
+}
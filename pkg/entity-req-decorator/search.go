@@ -0,0 +1,211 @@
+package entityreqdecorator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SearchRq тело запроса POST /api/{entity}/search:
+//
+//	{"filter":{"and":[{"field":"year","op":"gte","value":2015},{"or":[...]}]},
+//	 "sort":[{"field":"year","order":"DESC"}],"page":1,"size":20}
+type SearchRq struct {
+	Filter *SearchFilter `json:"filter"`
+	Sort   []SortBy      `json:"sort"`
+	Page   int           `json:"page"`
+	Size   int           `json:"size"`
+	// Cursor включает курсорную пагинацию, как параметр cursor в GET-запросе
+	Cursor    *string  `json:"cursor"`
+	WithTotal bool     `json:"withTotal"`
+	Fields    []string `json:"fields"`
+}
+
+// SearchFilter узел дерева фильтра: группа "and"/"or" или условие по полю.
+// Для in, nin и between value — массив, для isnull и notnull value не нужен.
+// Если op не задан, значение сравнивается на равенство.
+type SearchFilter struct {
+	And   []SearchFilter  `json:"and,omitempty"`
+	Or    []SearchFilter  `json:"or,omitempty"`
+	Field string          `json:"field,omitempty"`
+	Op    string          `json:"op,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// DecodeSearch разбирает тело запроса поиска в PagebleRq со строгой проверкой, как
+// ParseQueryParamsStrict. Фильтр попадает в PagebleRq.Where. Ошибки в теле возвращаются
+// в *QueryError с путем до ошибочного узла: "filter.and[1].value".
+func DecodeSearch(body io.Reader) (PagebleRq, error) {
+	var rq SearchRq
+	errs := &QueryError{}
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rq); err != nil && err != io.EOF {
+		errs.add("body", "invalid JSON: %s", err)
+		return PagebleRq{}, errs
+	}
+
+	req := PagebleRq{
+		Page:      PAGE,
+		Size:      SIZE,
+		Sort:      []SortBy{},
+		Filter:    make(map[string]SQLGenerator),
+		Strict:    true,
+		WithTotal: rq.WithTotal,
+		Fields:    rq.Fields,
+	}
+
+	switch {
+	case rq.Page > 0:
+		req.Page = rq.Page
+	case rq.Page < 0:
+		errs.add("page", "must be a positive integer")
+	}
+	switch {
+	case rq.Size > 0:
+		req.Size = rq.Size
+	case rq.Size < 0:
+		errs.add("size", "must be a positive integer")
+	}
+
+	if rq.Cursor != nil {
+		req.CursorMode = true
+		req.Cursor = *rq.Cursor
+		if rq.Page != 0 {
+			errs.add("page", "cannot be combined with cursor")
+		}
+	}
+
+	for i, s := range rq.Sort {
+		order := strings.ToUpper(strings.TrimSpace(s.Order))
+		if order == "" {
+			order = "ASC"
+		}
+		if s.Field == "" || (order != "ASC" && order != "DESC") {
+			errs.add(fmt.Sprintf("sort[%d]", i), "must have a field and order ASC or DESC")
+			continue
+		}
+		req.Sort = append(req.Sort, SortBy{Field: s.Field, Order: order})
+	}
+
+	if rq.Filter != nil {
+		req.Where = rq.Filter.predicate("filter", errs)
+	}
+
+	if err := errs.orNil(); err != nil {
+		return PagebleRq{}, err
+	}
+	return req, nil
+}
+
+// predicate переводит узел фильтра в предикат. Группы становятся PredicateANF/PredicateORF
+// без поля, условия — предикатами из predicatByStruct с полем из узла.
+func (f *SearchFilter) predicate(path string, errs *QueryError) SQLGenerator {
+	kinds := 0
+	for _, set := range []bool{f.And != nil, f.Or != nil, f.Field != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		errs.add(path, `must have exactly one of "and", "or" or "field"`)
+		return nil
+	}
+
+	switch {
+	case f.And != nil:
+		return &PredicateANF{Predicate: Predicate{InnerPredicate: searchGroup(f.And, path+".and", errs)}}
+	case f.Or != nil:
+		return &PredicateORF{Predicate: Predicate{InnerPredicate: searchGroup(f.Or, path+".or", errs)}}
+	}
+
+	op := f.Op
+	if op == "" {
+		op = "eq"
+	}
+	constructor, ok := predicatByStruct[op]
+	// Группы задаются через and/or, а не через оператор
+	if !ok || op == "anf" || op == "orf" {
+		errs.add(path+".op", "unknown operator %s", f.Op)
+		return nil
+	}
+	predicate := constructor("")
+	base := predicate.(interface{ base() *Predicate }).base()
+
+	switch op {
+	case "isnull", "notnull":
+	case "in", "nin", "between":
+		values, err := searchValues(f.Value)
+		if err != nil {
+			errs.add(path+".value", "%s", err)
+			return nil
+		}
+		base.Values = values
+	default:
+		value, err := searchValue(f.Value)
+		if err != nil {
+			errs.add(path+".value", "%s", err)
+			return nil
+		}
+		// Предикат с пустым значением QueryBuilder пропускает, и фильтр молча пропал бы из запроса
+		if value == "" {
+			errs.add(path+".value", "must not be empty; use isnull to match empty values")
+			return nil
+		}
+		base.Value = value
+	}
+	base.Field = f.Field
+	return predicate
+}
+
+// searchGroup переводит вложенные узлы группы; пустая группа считается ошибкой
+func searchGroup(filters []SearchFilter, path string, errs *QueryError) []SQLGenerator {
+	if len(filters) == 0 {
+		errs.add(path, "must not be empty")
+		return nil
+	}
+	predicates := make([]SQLGenerator, 0, len(filters))
+	for i := range filters {
+		if p := filters[i].predicate(fmt.Sprintf("%s[%d]", path, i), errs); p != nil {
+			predicates = append(predicates, p)
+		}
+	}
+	return predicates
+}
+
+// searchValue переводит скалярное JSON-значение в строку, которую разбирает Field.Parse:
+// строки берутся как есть, числа и true/false — в записи из JSON
+func searchValue(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", fmt.Errorf("is required; use isnull to match empty values")
+	}
+	switch raw[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("invalid string")
+		}
+		return s, nil
+	case '[', '{':
+		return "", fmt.Errorf("must be a string, number or boolean")
+	}
+	return string(raw), nil
+}
+
+// searchValues переводит JSON-массив значений для in, nin и between
+func searchValues(raw json.RawMessage) ([]string, error) {
+	var items []json.RawMessage
+	if len(raw) == 0 || raw[0] != '[' || json.Unmarshal(raw, &items) != nil {
+		return nil, fmt.Errorf("must be an array")
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		value, err := searchValue(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %s", i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,157 @@
+package entityreqdecorator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSearch(t *testing.T) {
+	body := `{
+		"filter": {"and": [
+			{"field": "age", "op": "gte", "value": 18},
+			{"or": [
+				{"field": "name", "op": "ilike", "value": "go"},
+				{"field": "email", "op": "endswith", "value": "@example.com"}
+			]},
+			{"field": "status", "op": "in", "value": ["new", "read"]},
+			{"field": "periodStart", "op": "notnull"},
+			{"field": "active", "value": true}
+		]},
+		"sort": [{"field": "age", "order": "desc"}],
+		"page": 2,
+		"size": 20
+	}`
+	req, err := DecodeSearch(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("DecodeSearch() error = %v", err)
+	}
+	if req.Page != 2 || req.Size != 20 || !req.Strict {
+		t.Errorf("DecodeSearch() page = %d, size = %d, strict = %v", req.Page, req.Size, req.Strict)
+	}
+
+	q, err := BuildListQuery(req, "SELECT * FROM users", testSchema)
+	if err != nil {
+		t.Fatalf("BuildListQuery() error = %v", err)
+	}
+	wantQuery := "SELECT * FROM users WHERE (age >= $1 AND (name ILIKE $2 OR email ILIKE $3) AND status IN ($4, $5) AND period_start IS NOT NULL AND active = $6) ORDER BY age DESC LIMIT $7 OFFSET $8"
+	if q.SelectQuery != wantQuery {
+		t.Errorf("SelectQuery = %v, want %v", q.SelectQuery, wantQuery)
+	}
+	wantParams := []interface{}{int64(18), "%go%", `%@example.com`, "new", "read", true, 20, 20}
+	if len(q.SelectParams) != len(wantParams) {
+		t.Fatalf("SelectParams = %v, want %v", q.SelectParams, wantParams)
+	}
+	for i := range wantParams {
+		if q.SelectParams[i] != wantParams[i] {
+			t.Errorf("SelectParams[%d] = %v, want %v", i, q.SelectParams[i], wantParams[i])
+		}
+	}
+}
+
+func TestDecodeSearchCursor(t *testing.T) {
+	req, err := DecodeSearch(strings.NewReader(`{"cursor": "", "size": 5, "withTotal": true, "fields": ["name"]}`))
+	if err != nil {
+		t.Fatalf("DecodeSearch() error = %v", err)
+	}
+	if !req.CursorMode || req.Size != 5 || !req.WithTotal || len(req.Fields) != 1 {
+		t.Errorf("DecodeSearch() = %+v", req)
+	}
+
+	req, err = DecodeSearch(strings.NewReader(""))
+	if err != nil || req.Where != nil || req.Page != PAGE || req.Size != SIZE {
+		t.Errorf("DecodeSearch() with empty body = %+v, %v", req, err)
+	}
+}
+
+func TestDecodeSearchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantParams []string
+	}{
+		{
+			name:       "invalid JSON",
+			body:       `{"filter":`,
+			wantParams: []string{"body"},
+		},
+		{
+			name:       "unknown key",
+			body:       `{"filters": {}}`,
+			wantParams: []string{"body"},
+		},
+		{
+			name:       "node with field and group",
+			body:       `{"filter": {"field": "age", "and": [{"field": "age", "value": 1}]}}`,
+			wantParams: []string{"filter"},
+		},
+		{
+			name:       "empty group",
+			body:       `{"filter": {"or": []}}`,
+			wantParams: []string{"filter.or"},
+		},
+		{
+			name:       "unknown operator",
+			body:       `{"filter": {"and": [{"field": "age", "op": "anf", "value": 1}, {"field": "age", "op": "regex", "value": 1}]}}`,
+			wantParams: []string{"filter.and[0].op", "filter.and[1].op"},
+		},
+		{
+			name:       "wrong value shape",
+			body:       `{"filter": {"or": [{"field": "age", "op": "in", "value": 1}, {"field": "age", "op": "eq", "value": [1]}, {"field": "age", "value": null}]}}`,
+			wantParams: []string{"filter.or[0].value", "filter.or[1].value", "filter.or[2].value"},
+		},
+		{
+			name:       "empty string value",
+			body:       `{"filter": {"and": [{"field": "name", "value": ""}, {"field": "name", "op": "ilike", "value": ""}, {"field": "name", "op": "startswith", "value": ""}, {"field": "name", "op": "endswith", "value": ""}, {"field": "name", "op": "isnull"}]}}`,
+			wantParams: []string{"filter.and[0].value", "filter.and[1].value", "filter.and[2].value", "filter.and[3].value"},
+		},
+		{
+			name:       "invalid pagination and sort",
+			body:       `{"page": -1, "cursor": "", "sort": [{"field": "age", "order": "up"}]}`,
+			wantParams: []string{"page", "page", "sort[0]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeSearch(strings.NewReader(tt.body))
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				t.Fatalf("DecodeSearch() error = %v, want *QueryError", err)
+			}
+			if len(qe.Params) != len(tt.wantParams) {
+				t.Fatalf("QueryError params = %v, want %v", qe.Params, tt.wantParams)
+			}
+			for i, param := range tt.wantParams {
+				if qe.Params[i].Param != param {
+					t.Errorf("QueryError params[%d] = %v, want %v", i, qe.Params[i].Param, param)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildListQueryWhereErrors(t *testing.T) {
+	req, err := DecodeSearch(strings.NewReader(`{"filter": {"or": [
+		{"field": "age", "value": "abc"},
+		{"field": "salary", "value": 1},
+		{"field": "password", "value": "x"},
+		{"field": "name", "op": "between", "value": ["a", "b"]}
+	]}}`))
+	if err != nil {
+		t.Fatalf("DecodeSearch() error = %v", err)
+	}
+	_, err = BuildListQuery(req, "SELECT * FROM users", testSchema)
+	var qe *QueryError
+	if !errors.As(err, &qe) {
+		t.Fatalf("BuildListQuery() error = %v, want *QueryError", err)
+	}
+	want := []string{"age", "salary", "password", "name"}
+	if len(qe.Params) != len(want) {
+		t.Fatalf("QueryError params = %v, want %v", qe.Params, want)
+	}
+	for i, param := range want {
+		if qe.Params[i].Param != param {
+			t.Errorf("QueryError params[%d] = %v, want %v", i, qe.Params[i].Param, param)
+		}
+	}
+}